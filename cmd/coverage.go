@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/zyra"
+)
+
+var (
+	coverageConfig string
+	coverageEnv    string
+)
+
+var coverageCmd = &cobra.Command{
+	Use:          "coverage [path]",
+	Short:        "Report the OpenAPI operations and status codes no request exercises",
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := "."
+		if len(args) == 1 {
+			path = args[0]
+		}
+
+		return zyra.Coverage(zyra.CoverageOptions{
+			Path:       path,
+			ConfigPath: coverageConfig,
+			Env:        coverageEnv,
+		})
+	},
+}
+
+func init() {
+	coverageCmd.Flags().StringVarP(&coverageConfig, "config", "c", "", "config file path")
+	coverageCmd.Flags().StringVarP(&coverageEnv, "env", "e", "", "environment profile from zyra.config")
+	rootCmd.AddCommand(coverageCmd)
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/zyra"
+)
+
+var envConfig string
+
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Manage environment profiles",
+}
+
+var envListCmd = &cobra.Command{
+	Use:   "list [path]",
+	Short: "List the environment profiles declared in zyra.config",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := "."
+		if len(args) == 1 {
+			path = args[0]
+		}
+
+		return zyra.ListEnvs(zyra.ListEnvsOptions{
+			Path:       path,
+			ConfigPath: envConfig,
+		})
+	},
+}
+
+func init() {
+	envListCmd.Flags().StringVarP(&envConfig, "config", "c", "", "config file path")
+	envCmd.AddCommand(envListCmd)
+	rootCmd.AddCommand(envCmd)
+}
@@ -1,18 +1,34 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
 	"os"
 
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/zyra"
 	"github.com/spf13/cobra"
 )
 
 var rootCmd = &cobra.Command{
-	Use:   "zyra",
-	Short: "Zyra is a file-based HTTP runner with TUI",
+	Use:           "zyra",
+	Short:         "Zyra is a file-based HTTP runner with TUI",
+	SilenceErrors: true,
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+	err := rootCmd.Execute()
+	if err == nil {
+		return
 	}
+
+	var exitErr *zyra.ExitError
+	if errors.As(err, &exitErr) {
+		if exitErr.Err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", exitErr.Err)
+		}
+		os.Exit(exitErr.Code)
+	}
+
+	fmt.Fprintln(os.Stderr, "Error:", err)
+	os.Exit(1)
 }
@@ -3,37 +3,83 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/Mahmoud-Khaled-FS/zyra/internal/zyra"
 	"github.com/spf13/cobra"
 )
 
 var runCmd = &cobra.Command{
-	Use:   "run [path]",
-	Short: "Run zyra file",
-	Args:  cobra.ExactArgs(1), // require exactly 1 argument
+	Use:          "run [path]",
+	Short:        "Run zyra file",
+	Args:         runArgs,
+	SilenceUsage: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if len(args) != 1 {
-			return fmt.Errorf("path is required")
-		}
-
 		if _, err := os.Stat(args[0]); err != nil {
-			return fmt.Errorf("invalid path: %s", args[0])
+			return invalidUsage(fmt.Errorf("invalid path: %s", args[0]))
 		}
 
 		cfg, err := cmd.Flags().GetString("config")
 		if err != nil {
-			return err
+			return invalidUsage(err)
 		}
 
 		noTest, err := cmd.Flags().GetBool("no-test")
 		if err != nil {
-			return err
+			return invalidUsage(err)
+		}
+
+		parallel, err := cmd.Flags().GetInt("parallel")
+		if err != nil {
+			return invalidUsage(err)
+		}
+
+		if parallel < 1 {
+			return invalidUsage(fmt.Errorf("invalid --parallel value: %d", parallel))
+		}
+
+		reporter, err := cmd.Flags().GetString("reporter")
+		if err != nil {
+			return invalidUsage(err)
+		}
+
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return invalidUsage(err)
+		}
+
+		env, err := cmd.Flags().GetString("env")
+		if err != nil {
+			return invalidUsage(err)
+		}
+
+		verbose, err := cmd.Flags().GetBool("verbose")
+		if err != nil {
+			return invalidUsage(err)
+		}
+
+		updateSnapshots, err := cmd.Flags().GetBool("update-snapshots")
+		if err != nil {
+			return invalidUsage(err)
+		}
+
+		varFlags, err := cmd.Flags().GetStringArray("var")
+		if err != nil {
+			return invalidUsage(err)
+		}
+
+		vars := make(map[string]string, len(varFlags))
+		for _, v := range varFlags {
+			key, val, ok := strings.Cut(v, "=")
+			if !ok || strings.TrimSpace(key) == "" {
+				return invalidUsage(fmt.Errorf("invalid --var %q, expected key=value", v))
+			}
+			vars[strings.TrimSpace(key)] = val
 		}
 
 		if cfg != "" {
 			if _, err := os.Stat(cfg); err != nil {
-				return fmt.Errorf("invalid path: %s", cfg)
+				return invalidUsage(fmt.Errorf("invalid path: %s", cfg))
 			}
 		}
 
@@ -42,6 +88,14 @@ var runCmd = &cobra.Command{
 			Path:       path,
 			ConfigPath: cfg,
 			NoTest:     noTest,
+			Parallel:   parallel,
+			Reporter:   reporter,
+			Output:     output,
+			Env:        env,
+			Vars:       vars,
+			Verbose:    verbose,
+
+			UpdateSnapshots: updateSnapshots,
 		})
 
 		if err != nil {
@@ -55,5 +109,29 @@ var runCmd = &cobra.Command{
 func init() {
 	runCmd.Flags().StringP("config", "c", "", "config file path")
 	runCmd.Flags().Bool("no-test", false, "skip test execution")
+	runCmd.Flags().IntP("parallel", "p", 1, "number of files to run concurrently")
+	runCmd.Flags().StringP("reporter", "r", "pretty", "report format: pretty, junit, json or tap")
+	runCmd.Flags().StringP("output", "o", "", "write the report to a file instead of stdout")
+	runCmd.Flags().StringP("env", "e", "", "environment profile from zyra.config")
+	runCmd.Flags().StringArray("var", nil, "set a variable, key=value (repeatable)")
+	runCmd.Flags().BoolP("verbose", "v", false, "show the timing breakdown of each request")
+	runCmd.Flags().Bool("update-snapshots", false, "rewrite snapshots that no longer match")
+	runCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return invalidUsage(err)
+	})
 	rootCmd.AddCommand(runCmd)
 }
+
+// runArgs requires exactly 1 argument.
+func runArgs(cmd *cobra.Command, args []string) error {
+	if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+		return invalidUsage(err)
+	}
+	return nil
+}
+
+// invalidUsage exits with the config code, so CI can tell a bad
+// invocation from failed assertions.
+func invalidUsage(err error) error {
+	return &zyra.ExitError{Code: zyra.ExitConfig, Err: err}
+}
@@ -3,7 +3,10 @@ package builtin
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/Mahmoud-Khaled-FS/zyra/internal/logger"
 )
@@ -40,30 +43,31 @@ func compare(actual any, expected any, op string) error {
 	eNum, eOk := toFloat64(expected)
 
 	if aOk && eOk {
+		a, e := numString(actual, aNum), numString(expected, eNum)
 		switch op {
 		case "==":
 			if aNum != eNum {
-				return fmt.Errorf("%v != %v", aNum, eNum)
+				return fmt.Errorf("%s != %s", a, e)
 			}
 		case "!=":
 			if aNum == eNum {
-				return fmt.Errorf("%v == %v", aNum, eNum)
+				return fmt.Errorf("%s == %s", a, e)
 			}
 		case ">":
 			if aNum <= eNum {
-				return fmt.Errorf("%v <= %v", aNum, eNum)
+				return fmt.Errorf("%s <= %s", a, e)
 			}
 		case ">=":
 			if aNum < eNum {
-				return fmt.Errorf("%v < %v", aNum, eNum)
+				return fmt.Errorf("%s < %s", a, e)
 			}
 		case "<":
 			if aNum >= eNum {
-				return fmt.Errorf("%v >= %v", aNum, eNum)
+				return fmt.Errorf("%s >= %s", a, e)
 			}
 		case "<=":
 			if aNum > eNum {
-				return fmt.Errorf("%v > %v", aNum, eNum)
+				return fmt.Errorf("%s > %s", a, e)
 			}
 		}
 		return nil
@@ -109,6 +113,10 @@ func fnHas(actual any, args []any) error {
 		if _, ok := v[key]; !ok {
 			return fmt.Errorf("missing key: %s", key)
 		}
+	case http.Header:
+		if len(v.Values(key)) == 0 {
+			return fmt.Errorf("missing header: %s", key)
+		}
 	case []any:
 		for _, item := range v {
 			if fmt.Sprintf("%v", item) == key {
@@ -199,6 +207,29 @@ func fnEndWith(actual any, args []any) error {
 	return nil
 }
 
+func fnMatches(actual any, args []any) error {
+	if len(args) != 1 {
+		return fmt.Errorf("matches expects 1 argument")
+	}
+
+	pattern, ok := args[0].(string)
+	if !ok {
+		return fmt.Errorf("matches argument must be string")
+	}
+
+	current := fmt.Sprintf("%v", actual)
+	matched, err := regexp.MatchString(pattern, current)
+	if err != nil {
+		return fmt.Errorf("invalid pattern %s: %w", pattern, err)
+	}
+
+	if !matched {
+		return fmt.Errorf("%s not matching %s", current, pattern)
+	}
+
+	return nil
+}
+
 func fnDebug(actual any, args []any) error {
 	logger.Debug("%s", logger.PrettyString(actual))
 	return nil
@@ -219,6 +250,14 @@ func toInt(v any) (int64, bool) {
 	}
 }
 
+// numString formats a compared number, durations keep their unit.
+func numString(v any, n float64) string {
+	if d, ok := v.(time.Duration); ok {
+		return d.String()
+	}
+	return fmt.Sprintf("%v", n)
+}
+
 func toFloat64(v any) (float64, bool) {
 	switch val := v.(type) {
 	case int:
@@ -227,6 +266,9 @@ func toFloat64(v any) (float64, bool) {
 		return float64(val), true
 	case float64:
 		return val, true
+	case time.Duration:
+		// durations compare in milliseconds, so `lt 500` means 500ms
+		return float64(val) / float64(time.Millisecond), true
 	case json.Number:
 		f, err := val.Float64()
 		if err != nil {
@@ -239,7 +281,8 @@ func toFloat64(v any) (float64, bool) {
 }
 
 // CheckType validates that `value` matches the expected type string.
-// Supported types: "json", "object", "array", "string", "int", "float", "bool", "null".
+// Supported types: "json", "object", "array", "string", "int", "float", "bool", "null",
+// "graphqlSuccess".
 func checkType(value any, expectedType string) error {
 	switch expectedType {
 	case "json":
@@ -301,9 +344,36 @@ func checkType(value any, expectedType string) error {
 			return fmt.Errorf("value is not null, got %T", value)
 		}
 
+	case "graphqlSuccess":
+		return checkGraphQLSuccess(value)
+
 	default:
 		return fmt.Errorf("unknown expected type: %s", expectedType)
 	}
 
 	return nil
 }
+
+// checkGraphQLSuccess fails on a GraphQL response body with a non-empty
+// errors array, whatever the status.
+func checkGraphQLSuccess(value any) error {
+	body, ok := value.(map[string]any)
+	if !ok {
+		return fmt.Errorf("value is not a GraphQL response, got %T", value)
+	}
+
+	errs, _ := body["errors"].([]any)
+	if len(errs) == 0 {
+		return nil
+	}
+
+	msgs := make([]string, 0, len(errs))
+	for _, e := range errs {
+		if m, ok := e.(map[string]any); ok && m["message"] != nil {
+			msgs = append(msgs, fmt.Sprintf("%v", m["message"]))
+			continue
+		}
+		msgs = append(msgs, fmt.Sprintf("%v", e))
+	}
+	return fmt.Errorf("graphql errors: %s", strings.Join(msgs, "; "))
+}
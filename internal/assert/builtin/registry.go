@@ -2,6 +2,7 @@ package builtin
 
 import (
 	"fmt"
+	"sync"
 )
 
 // EvalFunc defines the signature for all assertion functions.
@@ -9,19 +10,53 @@ import (
 // 'args' are the values passed in the DSL assertion.
 type EvalFunc func(actual any, args []any) error
 
+// Env describes where an assertion is evaluated.
+type Env struct {
+	// Dir is the directory of the file declaring the assertion, relative
+	// paths in arguments resolve against it.
+	Dir string
+
+	// Snapshot compares a value with the snapshot of the assertion, name
+	// overriding the default one. It is nil where snapshots are not
+	// supported, e.g. for global assertions.
+	Snapshot func(name string, actual any) error
+}
+
+// EnvEvalFunc is an EvalFunc that also needs the Env, e.g. to load files.
+type EnvEvalFunc func(env Env, actual any, args []any) error
+
 // FunctionRegistry stores all registered assertion functions.
 var FunctionRegistry = make(map[string]EvalFunc)
 
+// EnvFunctionRegistry stores the functions that need the Env.
+var EnvFunctionRegistry = make(map[string]EnvEvalFunc)
+
 // Register adds a new function to the registry.
 // Returns an error if the name already exists.
 func Register(name string, fn EvalFunc) error {
-	if _, exists := FunctionRegistry[name]; exists {
+	if exists(name) {
 		return fmt.Errorf("function '%s' already registered", name)
 	}
 	FunctionRegistry[name] = fn
 	return nil
 }
 
+// RegisterEnv adds a function that needs the Env.
+// Returns an error if the name already exists.
+func RegisterEnv(name string, fn EnvEvalFunc) error {
+	if exists(name) {
+		return fmt.Errorf("function '%s' already registered", name)
+	}
+	EnvFunctionRegistry[name] = fn
+	return nil
+}
+
+func exists(name string) bool {
+	_, plain := FunctionRegistry[name]
+	_, env := EnvFunctionRegistry[name]
+	return plain || env
+}
+
 // MustRegister panics if registration fails. Useful for built-ins.
 func MustRegister(name string, fn EvalFunc) {
 	if err := Register(name, fn); err != nil {
@@ -29,14 +64,44 @@ func MustRegister(name string, fn EvalFunc) {
 	}
 }
 
+// MustRegisterEnv panics if registration fails.
+func MustRegisterEnv(name string, fn EnvEvalFunc) {
+	if err := RegisterEnv(name, fn); err != nil {
+		panic(err)
+	}
+}
+
 // Get retrieves a function by name.
 func Get(name string) (EvalFunc, bool) {
 	fn, ok := FunctionRegistry[name]
 	return fn, ok
 }
 
-// InitBuiltin registers all built-in functions.
+// GetEnv retrieves a function by name, binding env to the ones that
+// need it.
+func GetEnv(name string, env Env) (EvalFunc, bool) {
+	if fn, ok := FunctionRegistry[name]; ok {
+		return fn, true
+	}
+
+	fn, ok := EnvFunctionRegistry[name]
+	if !ok {
+		return nil, false
+	}
+	return func(actual any, args []any) error {
+		return fn(env, actual, args)
+	}, true
+}
+
+var initOnce sync.Once
+
+// InitBuiltin registers all built-in functions. Calling it again is a
+// no-op.
 func InitBuiltin() {
+	initOnce.Do(registerBuiltins)
+}
+
+func registerBuiltins() {
 	MustRegister("eq", fnEq)
 	MustRegister("ne", fnNe)
 	MustRegister("gt", fnGt)
@@ -48,5 +113,8 @@ func InitBuiltin() {
 	MustRegister("len", fnLen)
 	MustRegister("startWith", fnStartWith)
 	MustRegister("endWith", fnEndWith)
+	MustRegister("matches", fnMatches)
 	MustRegister("debug", fnDebug)
+	MustRegisterEnv("matchesSchema", fnMatchesSchema)
+	MustRegisterEnv("matchesSnapshot", fnMatchesSnapshot)
 }
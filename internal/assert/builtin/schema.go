@@ -0,0 +1,86 @@
+package builtin
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// schemas caches compiled schemas by absolute path for the whole run.
+var schemas = struct {
+	sync.Mutex
+	compiled map[string]*jsonschema.Schema
+}{compiled: make(map[string]*jsonschema.Schema)}
+
+// fnMatchesSchema validates actual against a JSON Schema file, relative
+// to the .zyra file. Schemas without $schema are read as draft 2020-12.
+func fnMatchesSchema(env Env, actual any, args []any) error {
+	if len(args) != 1 {
+		return fmt.Errorf("matchesSchema expects 1 argument")
+	}
+	path, ok := args[0].(string)
+	if !ok {
+		return fmt.Errorf("schema path must be string")
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(env.Dir, path)
+	}
+
+	schema, err := compileSchema(path)
+	if err != nil {
+		return err
+	}
+
+	err = schema.Validate(actual)
+	if err == nil {
+		return nil
+	}
+
+	var ve *jsonschema.ValidationError
+	if !errors.As(err, &ve) {
+		return err
+	}
+	return fmt.Errorf("schema %s: %s", filepath.Base(path), strings.Join(violations(ve), "; "))
+}
+
+func compileSchema(path string) (*jsonschema.Schema, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	schemas.Lock()
+	defer schemas.Unlock()
+
+	if s, ok := schemas.compiled[abs]; ok {
+		return s, nil
+	}
+
+	s, err := jsonschema.NewCompiler().Compile(abs)
+	if err != nil {
+		return nil, fmt.Errorf("load schema: %w", err)
+	}
+	schemas.compiled[abs] = s
+	return s, nil
+}
+
+// violations lists every failed keyword as "pointer: message".
+func violations(ve *jsonschema.ValidationError) []string {
+	var out []string
+	for _, unit := range ve.BasicOutput().Errors {
+		if unit.Error == nil {
+			continue
+		}
+		pointer := unit.InstanceLocation
+		if pointer == "" {
+			pointer = "/"
+		}
+		out = append(out, pointer+": "+unit.Error.String())
+	}
+	return out
+}
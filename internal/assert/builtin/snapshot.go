@@ -0,0 +1,26 @@
+package builtin
+
+import (
+	"fmt"
+)
+
+// fnMatchesSnapshot compares actual with the snapshot recorded by an
+// earlier run, an optional argument names the snapshot.
+func fnMatchesSnapshot(env Env, actual any, args []any) error {
+	if len(args) > 1 {
+		return fmt.Errorf("matchesSnapshot expects at most 1 argument")
+	}
+	if env.Snapshot == nil {
+		return fmt.Errorf("matchesSnapshot is only supported in .zyra files")
+	}
+
+	var name string
+	if len(args) == 1 {
+		s, ok := args[0].(string)
+		if !ok {
+			return fmt.Errorf("snapshot name must be string")
+		}
+		name = s
+	}
+	return env.Snapshot(name, actual)
+}
@@ -0,0 +1,89 @@
+package assert
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/model"
+)
+
+// resolveCookies resolves paths over the cookies set by the response.
+// cookies is a name to value map, cookies.name the value and
+// cookies.name.attr one of the cookie attributes.
+func resolveCookies(cookies []*http.Cookie, path []model.PathSegment) (any, error) {
+	if len(path) == 0 {
+		all := make(map[string]any, len(cookies))
+		for _, c := range cookies {
+			all[c.Name] = c.Value
+		}
+		return all, nil
+	}
+
+	if path[0].Key == nil {
+		return nil, fmt.Errorf("invalid cookie path")
+	}
+
+	name := *path[0].Key
+	var cookie *http.Cookie
+	for _, c := range cookies {
+		if c.Name == name {
+			cookie = c
+		}
+	}
+	if cookie == nil {
+		return nil, fmt.Errorf("cookie not found: %s", name)
+	}
+
+	if len(path) == 1 {
+		return cookie.Value, nil
+	}
+
+	if len(path) > 2 || path[1].Key == nil {
+		return nil, fmt.Errorf("invalid cookie path")
+	}
+
+	return cookieAttribute(cookie, *path[1].Key)
+}
+
+func cookieAttribute(c *http.Cookie, attr string) (any, error) {
+	switch strings.ToLower(attr) {
+	case "name":
+		return c.Name, nil
+	case "value":
+		return c.Value, nil
+	case "path":
+		return c.Path, nil
+	case "domain":
+		return c.Domain, nil
+	case "expires":
+		if c.Expires.IsZero() {
+			return nil, nil
+		}
+		return c.Expires.UTC().Format(time.RFC3339), nil
+	case "maxage":
+		return c.MaxAge, nil
+	case "secure":
+		return c.Secure, nil
+	case "httponly":
+		return c.HttpOnly, nil
+	case "samesite":
+		return sameSiteString(c.SameSite), nil
+	default:
+		return nil, fmt.Errorf("unknown cookie attribute: %s", attr)
+	}
+}
+
+func sameSiteString(s http.SameSite) string {
+	switch s {
+	case http.SameSiteLaxMode:
+		return "lax"
+	case http.SameSiteStrictMode:
+		return "strict"
+	case http.SameSiteNoneMode:
+		return "none"
+	default:
+		return ""
+	}
+}
@@ -2,6 +2,7 @@ package assert
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/Mahmoud-Khaled-FS/zyra/internal/assert/builtin"
@@ -9,13 +10,14 @@ import (
 	"github.com/Mahmoud-Khaled-FS/zyra/internal/model"
 )
 
-func Evaluate(resp *httpclient.ZyraResponse, a *model.Assertion) error {
+// Evaluate checks a against resp. env locates the file declaring a.
+func Evaluate(resp *httpclient.ZyraResponse, a *model.Assertion, env builtin.Env) error {
 	value, err := ResolvePath(resp, a.Path)
 	if err != nil {
 		return fmt.Errorf("line %d: %w", a.Line, err)
 	}
 
-	fn, ok := builtin.Get(a.Fn)
+	fn, ok := builtin.GetEnv(a.Fn, env)
 	if !ok {
 		return fmt.Errorf("line %d: unknown function '%s'", a.Line, a.Fn)
 	}
@@ -29,12 +31,29 @@ func Evaluate(resp *httpclient.ZyraResponse, a *model.Assertion) error {
 				if err != nil {
 					args[i] = a.Raw
 				}
+				if m, ok := args[i].(Matches); ok {
+					args[i] = m.Values()
+				}
 			}
 			continue
 		}
 		args[i] = a.Raw
 	}
 
+	if a.Quantifier != "" {
+		if err := evaluateQuantifier(a.Quantifier, toMatches(value, a.Path), fn, args); err != nil {
+			return fmt.Errorf("line %d: %w", a.Line, err)
+		}
+		return nil
+	}
+
+	if matches, ok := value.(Matches); ok {
+		if err := evaluateAll(matches, fn, args); err != nil {
+			return fmt.Errorf("line %d: %w", a.Line, err)
+		}
+		return nil
+	}
+
 	if err := fn(value, args); err != nil {
 		return fmt.Errorf("line %d: %w", a.Line, err)
 	}
@@ -64,12 +83,20 @@ func ResolvePath(resp *httpclient.ZyraResponse, path []model.PathSegment) (any,
 	case "body":
 		return resolveBody(resp.Body, path[1:])
 
+	case "cookies":
+		return resolveCookies(resp.Cookies, path[1:])
+
+	case "timing":
+		return resolveTiming(resp.Timing, path[1:])
+
 	default:
 		return nil, fmt.Errorf("unknown root: %s", *seg.Key)
 	}
 }
 
-func resolveHeaders(headers map[string]string, path []model.PathSegment) (any, error) {
+// resolveHeaders looks headers up case-insensitively. headers.Name is the
+// first value, headers.Name[i] a single value and headers.Name[*] all of them.
+func resolveHeaders(headers http.Header, path []model.PathSegment) (any, error) {
 	if len(path) == 0 {
 		return headers, nil
 	}
@@ -79,15 +106,47 @@ func resolveHeaders(headers map[string]string, path []model.PathSegment) (any, e
 	}
 
 	key := *path[0].Key
-	val, ok := headers[key]
-	if !ok {
+	values := headers.Values(key)
+	if len(values) == 0 {
 		return nil, fmt.Errorf("header not found: %s", key)
 	}
 
-	return val, nil
+	if len(path) == 1 {
+		return values[0], nil
+	}
+
+	if len(path) > 2 {
+		return nil, fmt.Errorf("invalid header path")
+	}
+
+	seg := path[1]
+	switch {
+	case seg.Wildcard:
+		all := make(Matches, len(values))
+		for i, v := range values {
+			all[i] = Match{Path: fmt.Sprintf("headers%s[%d]", keyPath(key), i), Value: v}
+		}
+		return all, nil
+
+	case seg.Index != nil:
+		i, ok := index(*seg.Index, len(values))
+		if !ok {
+			return nil, fmt.Errorf("index out of range: %d", *seg.Index)
+		}
+		return values[i], nil
+
+	default:
+		return nil, fmt.Errorf("invalid header path")
+	}
 }
 
+// resolveBody walks path in the body. Paths that can select several
+// values resolve to Matches.
 func resolveBody(body any, path []model.PathSegment) (any, error) {
+	if isMulti(path) {
+		return resolveMatches(Match{Path: "body", Value: body}, path)
+	}
+
 	current := body
 
 	for _, seg := range path {
@@ -107,10 +166,11 @@ func resolveBody(body any, path []model.PathSegment) (any, error) {
 			if seg.Index == nil {
 				return nil, fmt.Errorf("expected index, got key")
 			}
-			if *seg.Index < 0 || *seg.Index >= len(v) {
+			i, ok := index(*seg.Index, len(v))
+			if !ok {
 				return nil, fmt.Errorf("index out of range: %d", *seg.Index)
 			}
-			current = v[*seg.Index]
+			current = v[i]
 
 		default:
 			return nil, fmt.Errorf("cannot traverse %T", current)
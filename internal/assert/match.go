@@ -0,0 +1,210 @@
+package assert
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/assert/builtin"
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/model"
+)
+
+// Match is one value selected by a path, Path names it, e.g. body.items[2].id.
+type Match struct {
+	Path  string
+	Value any
+}
+
+// Matches is what a path with wildcards, slices, recursive descent or
+// filters resolves to. Assertions apply to every match.
+type Matches []Match
+
+// Values returns the matched values in order.
+func (m Matches) Values() []any {
+	values := make([]any, len(m))
+	for i, match := range m {
+		values[i] = match.Value
+	}
+	return values
+}
+
+// isMulti reports a path that can select several values.
+func isMulti(path []model.PathSegment) bool {
+	for _, seg := range path {
+		if seg.Multi() {
+			return true
+		}
+	}
+	return false
+}
+
+// filterFns maps filter comparisons to the assertion functions.
+var filterFns = map[string]string{
+	"==": "eq",
+	"!=": "ne",
+	">":  "gt",
+	">=": "gte",
+	"<":  "lt",
+	"<=": "lte",
+}
+
+// resolveMatches walks path from every match, missing keys and out of
+// range indexes select nothing.
+func resolveMatches(root Match, path []model.PathSegment) (Matches, error) {
+	matches := Matches{root}
+
+	for _, seg := range path {
+		var next Matches
+		for _, m := range matches {
+			selected, err := selectSegment(m, seg)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, selected...)
+		}
+		matches = next
+	}
+	return matches, nil
+}
+
+func selectSegment(m Match, seg model.PathSegment) (Matches, error) {
+	if seg.Recursive {
+		return descend(m, seg), nil
+	}
+
+	switch v := m.Value.(type) {
+	case map[string]any:
+		switch {
+		case seg.Key != nil:
+			if val, ok := v[*seg.Key]; ok {
+				return Matches{{Path: m.Path + keyPath(*seg.Key), Value: val}}, nil
+			}
+			return nil, nil
+		case seg.Wildcard:
+			return children(m), nil
+		case seg.Filter != nil:
+			return filterMatches(children(m), seg.Filter)
+		}
+
+	case []any:
+		switch {
+		case seg.Index != nil:
+			i, ok := index(*seg.Index, len(v))
+			if !ok {
+				return nil, nil
+			}
+			return Matches{{Path: fmt.Sprintf("%s[%d]", m.Path, i), Value: v[i]}}, nil
+		case seg.Wildcard:
+			return children(m), nil
+		case seg.Slice != nil:
+			start, end := sliceBounds(seg.Slice, len(v))
+			var out Matches
+			for i := start; i < end; i++ {
+				out = append(out, Match{Path: fmt.Sprintf("%s[%d]", m.Path, i), Value: v[i]})
+			}
+			return out, nil
+		case seg.Filter != nil:
+			return filterMatches(children(m), seg.Filter)
+		}
+	}
+	return nil, nil
+}
+
+// children returns the elements of an array, or the values of an
+// object in key order.
+func children(m Match) Matches {
+	var out Matches
+	switch v := m.Value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			out = append(out, Match{Path: m.Path + keyPath(k), Value: v[k]})
+		}
+	case []any:
+		for i, item := range v {
+			out = append(out, Match{Path: fmt.Sprintf("%s[%d]", m.Path, i), Value: item})
+		}
+	}
+	return out
+}
+
+// descend selects seg.Key, or every value for ..*, at any depth below m.
+func descend(m Match, seg model.PathSegment) Matches {
+	var out Matches
+	if obj, ok := m.Value.(map[string]any); ok && seg.Key != nil {
+		if val, ok := obj[*seg.Key]; ok {
+			out = append(out, Match{Path: m.Path + keyPath(*seg.Key), Value: val})
+		}
+	}
+
+	for _, c := range children(m) {
+		if seg.Wildcard {
+			out = append(out, c)
+		}
+		out = append(out, descend(c, seg)...)
+	}
+	return out
+}
+
+func filterMatches(candidates Matches, f *model.Filter) (Matches, error) {
+	var fn builtin.EvalFunc
+	if f.Op != "" {
+		var ok bool
+		fn, ok = builtin.Get(filterFns[f.Op])
+		if !ok {
+			return nil, fmt.Errorf("unknown filter operator: %s", f.Op)
+		}
+	}
+
+	expected := f.Value.Raw
+	if f.Value.Type == "key" && expected == "null" {
+		expected = nil
+	}
+
+	var out Matches
+	for _, c := range candidates {
+		value, err := resolveBody(c.Value, f.Path)
+		if err != nil {
+			continue
+		}
+		if fn == nil || fn(value, []any{expected}) == nil {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+// index resolves a negative index from the end.
+func index(i, n int) (int, bool) {
+	if i < 0 {
+		i += n
+	}
+	return i, i >= 0 && i < n
+}
+
+func sliceBounds(s *model.Slice, n int) (int, int) {
+	bound := func(b *int, def int) int {
+		if b == nil {
+			return def
+		}
+		i := *b
+		if i < 0 {
+			i += n
+		}
+		return min(max(i, 0), n)
+	}
+	return bound(s.Start, 0), bound(s.End, n)
+}
+
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+func keyPath(key string) string {
+	if identifier.MatchString(key) {
+		return "." + key
+	}
+	return fmt.Sprintf("[%q]", key)
+}
@@ -0,0 +1,96 @@
+package assert
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/assert/builtin"
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/model"
+)
+
+// evaluateQuantifier applies fn over matches:
+//
+//	all    every match passes, the default for multi-value paths
+//	any    at least one match passes
+//	none   no match passes
+//	count  fn checks the number of matches
+func evaluateQuantifier(q string, matches Matches, fn builtin.EvalFunc, args []any) error {
+	switch q {
+	case model.QuantifierAll:
+		return evaluateAll(matches, fn, args)
+
+	case model.QuantifierAny:
+		for _, m := range matches {
+			if fn(m.Value, args) == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("none of %d values matched", len(matches))
+
+	case model.QuantifierNone:
+		for _, m := range matches {
+			if fn(m.Value, args) == nil {
+				return fmt.Errorf("%s matched", m.Path)
+			}
+		}
+		return nil
+
+	case model.QuantifierCount:
+		if err := fn(len(matches), args); err != nil {
+			return fmt.Errorf("count: %w", err)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown quantifier: %s", q)
+	}
+}
+
+// evaluateAll applies fn to every match and names the first one that
+// fails. A path that matched nothing fails.
+func evaluateAll(matches Matches, fn builtin.EvalFunc, args []any) error {
+	if len(matches) == 0 {
+		return fmt.Errorf("path matched no values")
+	}
+
+	for _, m := range matches {
+		if err := fn(m.Value, args); err != nil {
+			return fmt.Errorf("%s: %w", m.Path, err)
+		}
+	}
+	return nil
+}
+
+// toMatches turns the value of path into matches: the matches of a
+// multi-value path, the elements of an array, or the value itself.
+func toMatches(value any, path []model.PathSegment) Matches {
+	switch v := value.(type) {
+	case Matches:
+		return v
+	case []any:
+		name := pathName(path)
+		out := make(Matches, len(v))
+		for i, item := range v {
+			out[i] = Match{Path: fmt.Sprintf("%s[%d]", name, i), Value: item}
+		}
+		return out
+	default:
+		return Matches{{Path: pathName(path), Value: value}}
+	}
+}
+
+// pathName writes a single-value path back, e.g. body.items[0].id.
+func pathName(path []model.PathSegment) string {
+	var b strings.Builder
+	for i, seg := range path {
+		switch {
+		case seg.Key != nil && i == 0:
+			b.WriteString(*seg.Key)
+		case seg.Key != nil:
+			b.WriteString(keyPath(*seg.Key))
+		case seg.Index != nil:
+			fmt.Fprintf(&b, "[%d]", *seg.Index)
+		}
+	}
+	return b.String()
+}
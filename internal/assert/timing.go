@@ -0,0 +1,41 @@
+package assert
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	httpclient "github.com/Mahmoud-Khaled-FS/zyra/internal/httpClient"
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/model"
+)
+
+// resolveTiming resolves timing.phase to a time.Duration, timing alone
+// is a phase to duration map.
+func resolveTiming(t httpclient.Timing, path []model.PathSegment) (any, error) {
+	phases := map[string]time.Duration{
+		"dns":      t.DNS,
+		"connect":  t.Connect,
+		"tls":      t.TLS,
+		"ttfb":     t.TTFB,
+		"download": t.Download,
+		"total":    t.Total,
+	}
+
+	if len(path) == 0 {
+		all := make(map[string]any, len(phases))
+		for k, v := range phases {
+			all[k] = v
+		}
+		return all, nil
+	}
+
+	if path[0].Key == nil || len(path) > 1 {
+		return nil, fmt.Errorf("invalid timing path")
+	}
+
+	d, ok := phases[strings.ToLower(*path[0].Key)]
+	if !ok {
+		return nil, fmt.Errorf("unknown timing: %s", *path[0].Key)
+	}
+	return d, nil
+}
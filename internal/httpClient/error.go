@@ -0,0 +1,114 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"syscall"
+)
+
+type TransportErrorKind int
+
+const (
+	TransportUnknown TransportErrorKind = iota
+	TransportInvalidURL
+	TransportDNS
+	TransportConnectionRefused
+	TransportTimeout
+	TransportTLS
+)
+
+func (k TransportErrorKind) String() string {
+	switch k {
+	case TransportInvalidURL:
+		return "invalid url"
+	case TransportDNS:
+		return "dns lookup failed"
+	case TransportConnectionRefused:
+		return "connection refused"
+	case TransportTimeout:
+		return "timeout"
+	case TransportTLS:
+		return "tls error"
+	default:
+		return "transport error"
+	}
+}
+
+// TransportError reports a request that never got a response.
+type TransportError struct {
+	Kind TransportErrorKind
+	URL  string
+	Err  error
+}
+
+func (e *TransportError) Error() string {
+	return fmt.Sprintf("%s (%s): %v", e.Kind, e.URL, e.Err)
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+func newTransportError(rawURL string, err error) *TransportError {
+	// *url.Error repeats the method and URL, keep only the cause.
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+
+	return &TransportError{
+		Kind: classifyTransportError(err),
+		URL:  rawURL,
+		Err:  err,
+	}
+}
+
+func classifyTransportError(err error) TransportErrorKind {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return TransportDNS
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return TransportTimeout
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return TransportTimeout
+	}
+
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return TransportConnectionRefused
+	}
+
+	var (
+		recordErr    tls.RecordHeaderError
+		verifyErr    *tls.CertificateVerificationError
+		alertErr     tls.AlertError
+		authorityErr x509.UnknownAuthorityError
+		hostnameErr  x509.HostnameError
+		invalidErr   x509.CertificateInvalidError
+	)
+	if errors.As(err, &recordErr) || errors.As(err, &verifyErr) || errors.As(err, &alertErr) ||
+		errors.As(err, &authorityErr) || errors.As(err, &hostnameErr) || errors.As(err, &invalidErr) {
+		return TransportTLS
+	}
+
+	msg := err.Error()
+	if strings.Contains(msg, "tls: ") || strings.Contains(msg, "server gave HTTP response to HTTPS client") {
+		return TransportTLS
+	}
+
+	if strings.Contains(msg, "unsupported protocol scheme") || strings.Contains(msg, "no Host in request URL") {
+		return TransportInvalidURL
+	}
+
+	return TransportUnknown
+}
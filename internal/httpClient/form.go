@@ -0,0 +1,81 @@
+package httpclient
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/model"
+)
+
+const formContentType = "application/x-www-form-urlencoded"
+
+// encodeForm encodes params in declared order, unlike url.Values which
+// sorts them.
+func encodeForm(params []model.Param) string {
+	pairs := make([]string, 0, len(params))
+	for _, p := range params {
+		pairs = append(pairs, url.QueryEscape(p.Key)+"="+url.QueryEscape(p.Value))
+	}
+	return strings.Join(pairs, "&")
+}
+
+// multipartBody streams parts through a pipe, so files are read while
+// the request is sent instead of being loaded in memory. It returns the
+// body and its Content-Type.
+func multipartBody(parts []model.Part) (io.ReadCloser, string) {
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+
+	go func() {
+		for _, part := range parts {
+			if err := writePart(mw, part); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+		pw.CloseWithError(mw.Close())
+	}()
+
+	return pr, mw.FormDataContentType()
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func writePart(mw *multipart.Writer, part model.Part) error {
+	if !part.File {
+		return mw.WriteField(part.Key, part.Value)
+	}
+
+	f, err := os.Open(part.Value)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	contentType := part.Type
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(part.Value))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+		quoteEscaper.Replace(part.Key), quoteEscaper.Replace(filepath.Base(part.Value))))
+	h.Set("Content-Type", contentType)
+
+	w, err := mw.CreatePart(h)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, f)
+	return err
+}
@@ -0,0 +1,218 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Options controls how a request is sent. It is built from the
+// [options] section of zyra.config and of each document.
+type Options struct {
+	Timeout            time.Duration
+	FollowRedirects    bool
+	MaxRedirects       int
+	InsecureSkipVerify bool
+	CACert             string
+	ClientCert         string
+	ClientKey          string
+	Proxy              string
+}
+
+func DefaultOptions() Options {
+	return Options{
+		Timeout:         30 * time.Second,
+		FollowRedirects: true,
+		MaxRedirects:    10,
+	}
+}
+
+// ParseOptions reads the HTTP keys of an [options] section on top of
+// the defaults. Keys it does not know are left to other consumers.
+func ParseOptions(values map[string]string) (Options, error) {
+	opts := DefaultOptions()
+
+	var err error
+	for k, v := range values {
+		switch k {
+		case "timeout":
+			opts.Timeout, err = parseTimeout(v)
+		case "follow_redirects":
+			opts.FollowRedirects, err = strconv.ParseBool(v)
+		case "max_redirects":
+			opts.MaxRedirects, err = strconv.Atoi(v)
+		case "insecure_skip_verify":
+			opts.InsecureSkipVerify, err = strconv.ParseBool(v)
+		case "ca_cert":
+			opts.CACert = v
+		case "client_cert":
+			opts.ClientCert = v
+		case "client_key":
+			opts.ClientKey = v
+		case "proxy":
+			opts.Proxy = v
+		}
+
+		if err != nil {
+			return opts, fmt.Errorf("invalid option %s: %q", k, v)
+		}
+	}
+
+	if (opts.ClientCert == "") != (opts.ClientKey == "") {
+		return opts, fmt.Errorf("client_cert and client_key must be set together")
+	}
+
+	return opts, nil
+}
+
+// parseTimeout accepts Go durations (5s, 1500ms) or plain seconds.
+func parseTimeout(v string) (time.Duration, error) {
+	if secs, err := strconv.ParseFloat(v, 64); err == nil {
+		return time.Duration(secs * float64(time.Second)), nil
+	}
+	return time.ParseDuration(v)
+}
+
+// NewClient builds the client the options describe, with a transport of
+// its own. It fails on unreadable certificates or an invalid proxy.
+func (o Options) NewClient() (*http.Client, error) {
+	transport, err := o.newTransport()
+	if err != nil {
+		return nil, err
+	}
+	return o.client(transport), nil
+}
+
+func (o Options) client(transport http.RoundTripper) *http.Client {
+	return &http.Client{
+		Timeout:       o.Timeout,
+		Transport:     transport,
+		CheckRedirect: o.checkRedirect,
+	}
+}
+
+func (o Options) newTransport() (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	tlsConfig, err := o.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	if o.Proxy != "" {
+		proxy, err := url.Parse(o.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	return transport, nil
+}
+
+// transportKey holds the options a transport is built from.
+type transportKey struct {
+	insecureSkipVerify bool
+	caCert             string
+	clientCert         string
+	clientKey          string
+	proxy              string
+}
+
+func (o Options) transportKey() transportKey {
+	return transportKey{
+		insecureSkipVerify: o.InsecureSkipVerify,
+		caCert:             o.CACert,
+		clientCert:         o.ClientCert,
+		clientKey:          o.ClientKey,
+		proxy:              o.Proxy,
+	}
+}
+
+// Transports shares one transport per TLS and proxy setting, so the
+// requests of a run reuse their connections.
+type Transports struct {
+	mu         sync.Mutex
+	transports map[transportKey]*http.Transport
+}
+
+func NewTransports() *Transports {
+	return &Transports{transports: make(map[transportKey]*http.Transport)}
+}
+
+// Client builds a client for o on the shared transport of its setting.
+func (t *Transports) Client(o Options) (*http.Client, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := o.transportKey()
+	transport, ok := t.transports[key]
+	if !ok {
+		var err error
+		transport, err = o.newTransport()
+		if err != nil {
+			return nil, err
+		}
+		t.transports[key] = transport
+	}
+	return o.client(transport), nil
+}
+
+// CloseIdleConnections closes the idle connections of every transport.
+func (t *Transports) CloseIdleConnections() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, transport := range t.transports {
+		transport.CloseIdleConnections()
+	}
+}
+
+func (o Options) checkRedirect(req *http.Request, via []*http.Request) error {
+	if !o.FollowRedirects {
+		return http.ErrUseLastResponse
+	}
+	if len(via) >= o.MaxRedirects {
+		return fmt.Errorf("stopped after %d redirects", o.MaxRedirects)
+	}
+	return nil
+}
+
+func (o Options) tlsConfig() (*tls.Config, error) {
+	cfg := &tls.Config{
+		InsecureSkipVerify: o.InsecureSkipVerify,
+	}
+
+	if o.CACert != "" {
+		pem, err := os.ReadFile(o.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("ca_cert: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("ca_cert: no certificates found in %s", o.CACert)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if o.ClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(o.ClientCert, o.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("client_cert: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
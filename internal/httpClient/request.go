@@ -1,79 +1,179 @@
 package httpclient
 
 import (
-	"bytes"
+	"context"
+	"io"
 	"net/http"
+	"net/http/httptrace"
+	"net/url"
 	"strings"
-	"time"
+
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/model"
 )
 
 type Request struct {
 	Method  string
 	URL     string
-	Headers map[string]string
-	Body    string
-	Queries map[string]string
+	Headers []model.Param
+	Body    io.Reader
+	Queries []model.Param
+	Options Options
+	Jar     http.CookieJar
+
+	// Client sends the request, one is built from Options when nil.
+	Client *http.Client
+
+	// ContentLength is the size of Body, -1 when unknown.
+	ContentLength int64
+
+	// Multipart is sent instead of Body when set.
+	Multipart []model.Part
+
+	// contentType is sent unless a Content-Type header is given.
+	contentType string
 }
 
 func NewRequest(method string, url string) *Request {
 	return &Request{
-		Method: method,
-		URL:    url,
+		Method:  method,
+		URL:     url,
+		Options: DefaultOptions(),
 	}
 }
 
-func (r *Request) AddHeaders(headers map[string]string) {
+func (r *Request) SetOptions(options Options) {
+	r.Options = options
+}
+
+// SetClient sends the request with client instead of one built from
+// the options.
+func (r *Request) SetClient(client *http.Client) {
+	r.Client = client
+}
+
+func (r *Request) SetCookieJar(jar http.CookieJar) {
+	r.Jar = jar
+}
+
+func (r *Request) AddHeaders(headers []model.Param) {
 	r.Headers = headers
 }
 
-func (r *Request) AddQueries(queries map[string]string) {
+func (r *Request) AddQueries(queries []model.Param) {
 	r.Queries = queries
 }
 
 func (r *Request) AddBody(body string) {
+	r.SetBody(strings.NewReader(body), int64(len(body)))
+}
+
+// AddForm sends params as an urlencoded form.
+func (r *Request) AddForm(params []model.Param) {
+	r.AddBody(encodeForm(params))
+	r.SetContentType(formContentType)
+}
+
+// SetContentType sets the Content-Type sent when the request headers
+// do not have one.
+func (r *Request) SetContentType(contentType string) {
+	r.contentType = contentType
+}
+
+// AddMultipart sends parts as multipart/form-data.
+func (r *Request) AddMultipart(parts []model.Part) {
+	r.Multipart = parts
+}
+
+// SetBody sends body, size bytes long. A body that is an io.Seeker is
+// rewound when a redirect resends it.
+func (r *Request) SetBody(body io.Reader, size int64) {
 	r.Body = body
+	r.ContentLength = size
 }
 
-func (r *Request) Run() (*ZyraResponse, error) {
-	start := time.Now()
+// body hides Close from the transport, the caller owns the reader.
+func (r *Request) body() io.Reader {
+	if r.Body == nil || r.ContentLength == 0 {
+		return nil
+	}
+	if _, ok := r.Body.(io.Closer); ok {
+		return io.NopCloser(r.Body)
+	}
+	return r.Body
+}
 
-	var sb strings.Builder
+func (r *Request) setBodyLength(httpReq *http.Request) {
+	if r.Body == nil || len(r.Multipart) > 0 || httpReq.Body == nil || httpReq.GetBody != nil {
+		return
+	}
 
-	sb.WriteString(r.URL)
+	httpReq.ContentLength = r.ContentLength
+	if r.ContentLength < 0 {
+		httpReq.ContentLength = 0 // unknown, sent chunked
+	}
 
-	if len(r.Queries) > 0 {
-		sb.WriteString("?")
-		pairs := make([]string, 0, len(r.Queries))
-		for k, v := range r.Queries {
-			pairs = append(pairs, k+"="+v)
+	if s, ok := r.Body.(io.Seeker); ok {
+		httpReq.GetBody = func() (io.ReadCloser, error) {
+			if _, err := s.Seek(0, io.SeekStart); err != nil {
+				return nil, err
+			}
+			return io.NopCloser(r.Body), nil
 		}
+	}
+}
+
+func (r *Request) Run() (*ZyraResponse, error) {
+	trace := newTracer()
 
-		sb.WriteString(strings.Join(pairs, "&"))
+	url, err := r.buildURL()
+	if err != nil {
+		return nil, &TransportError{Kind: TransportInvalidURL, URL: r.URL, Err: err}
+	}
+
+	client := r.Client
+	if client == nil {
+		client, err = r.Options.NewClient()
+		if err != nil {
+			return nil, err
+		}
+		defer client.CloseIdleConnections()
 	}
+	client.Jar = r.Jar
 
-	url := sb.String()
+	body, contentType := r.body(), r.contentType
+	if len(r.Multipart) > 0 {
+		mp, ct := multipartBody(r.Multipart)
+		defer mp.Close()
+		body, contentType = mp, ct
+	}
 
-	httpReq, err := http.NewRequest(
+	httpReq, err := http.NewRequestWithContext(
+		httptrace.WithClientTrace(context.Background(), trace.clientTrace()),
 		strings.ToUpper(r.Method),
 		url,
-		bytes.NewBufferString(r.Body),
+		body,
 	)
 
 	if err != nil {
-		panic(err)
+		return nil, &TransportError{Kind: TransportInvalidURL, URL: url, Err: err}
 	}
+	r.setBodyLength(httpReq)
 
-	for k, v := range r.Headers {
-		httpReq.Header.Set(k, v)
+	for _, h := range r.Headers {
+		if strings.EqualFold(h.Key, "Host") {
+			httpReq.Host = h.Value
+			continue
+		}
+		httpReq.Header.Add(h.Key, h.Value)
 	}
 
-	client := &http.Client{
-		Timeout: 30 * time.Second,
+	if contentType != "" && httpReq.Header.Get("Content-Type") == "" {
+		httpReq.Header.Set("Content-Type", contentType)
 	}
 
 	resp, err := client.Do(httpReq)
 	if err != nil {
-		panic(err)
+		return nil, newTransportError(url, err)
 	}
 
 	// fmt.Println("Request>>>")
@@ -91,7 +191,35 @@ func (r *Request) Run() (*ZyraResponse, error) {
 		return nil, err
 	}
 
-	zr.Duration = time.Since(start)
+	zr.Timing = trace.done()
+	zr.Duration = zr.Timing.Total
 
 	return zr, nil
 }
+
+// buildURL appends the [query] params in declaration order, keeping any
+// query string already present in the request line.
+func (r *Request) buildURL() (string, error) {
+	if len(r.Queries) == 0 {
+		return r.URL, nil
+	}
+
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return "", err
+	}
+
+	pairs := make([]string, 0, len(r.Queries))
+	for _, q := range r.Queries {
+		pairs = append(pairs, url.QueryEscape(q.Key)+"="+url.QueryEscape(q.Value))
+	}
+	query := strings.Join(pairs, "&")
+
+	if u.RawQuery != "" {
+		u.RawQuery += "&" + query
+	} else {
+		u.RawQuery = query
+	}
+
+	return u.String(), nil
+}
@@ -26,9 +26,11 @@ type ZyraResponse struct {
 	Status   int
 	RawBody  []byte
 	Body     any
-	Headers  map[string]string
+	Headers  http.Header
+	Cookies  []*http.Cookie
 	BodyType BodyType
 	Duration time.Duration
+	Timing   Timing
 }
 
 func NewResponse(resp *http.Response) (*ZyraResponse, error) {
@@ -37,14 +39,8 @@ func NewResponse(resp *http.Response) (*ZyraResponse, error) {
 		Status: resp.StatusCode,
 	}
 
-	var headers map[string]string = make(map[string]string, len(resp.Header))
-
-	for k, v := range resp.Header {
-		if len(v) > 0 {
-			headers[k] = v[0]
-		}
-	}
-	zr.Headers = headers
+	zr.Headers = resp.Header.Clone()
+	zr.Cookies = resp.Cookies()
 
 	rawBody, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -0,0 +1,102 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Timing is the breakdown of a request. DNS, Connect and TLS are zero
+// when a connection is reused. TTFB runs from the start of the request
+// to the first response byte, Download from there to the end of the body.
+type Timing struct {
+	DNS      time.Duration
+	Connect  time.Duration
+	TLS      time.Duration
+	TTFB     time.Duration
+	Download time.Duration
+	Total    time.Duration
+}
+
+// tracer records the phases of a request through httptrace. With
+// redirects, each phase holds the last hop that went through it.
+// Callbacks can run concurrently, e.g. when several addresses are
+// dialed at once, so every field is guarded by mu.
+type tracer struct {
+	mu sync.Mutex
+
+	start     time.Time
+	dnsStart  time.Time
+	tlsStart  time.Time
+	firstByte time.Time
+
+	// connStart is keyed by the dialed address, Connect is the dial
+	// that succeeded.
+	connStart map[string]time.Time
+
+	timing Timing
+}
+
+func newTracer() *tracer {
+	return &tracer{
+		start:     time.Now(),
+		connStart: make(map[string]time.Time),
+	}
+}
+
+func (t *tracer) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.dnsStart = time.Now()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.timing.DNS = time.Since(t.dnsStart)
+		},
+		ConnectStart: func(network, addr string) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.connStart[network+" "+addr] = time.Now()
+		},
+		ConnectDone: func(network, addr string, err error) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if start, ok := t.connStart[network+" "+addr]; ok && err == nil {
+				t.timing.Connect = time.Since(start)
+			}
+		},
+		TLSHandshakeStart: func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.timing.TLS = time.Since(t.tlsStart)
+		},
+		GotFirstResponseByte: func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.firstByte = time.Now()
+			t.timing.TTFB = t.firstByte.Sub(t.start)
+		},
+	}
+}
+
+// done closes the timing once the body is read.
+func (t *tracer) done() Timing {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	end := time.Now()
+	t.timing.Total = end.Sub(t.start)
+	if !t.firstByte.IsZero() {
+		t.timing.Download = end.Sub(t.firstByte)
+	}
+	return t.timing
+}
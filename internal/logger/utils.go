@@ -6,6 +6,7 @@ import (
 
 type RequestMeta struct {
 	FilePath   string `json:"filePath"`
+	Name       string `json:"name,omitempty"`
 	Method     string `json:"method"`
 	URL        string `json:"url"`
 	Assertions int    `json:"assertions"`
@@ -24,6 +25,7 @@ const (
 	bgCyan    = "\033[46m"
 	bgMagenta = "\033[45m"
 	bgWhite   = "\033[47m"
+	bgGray    = "\033[100m"
 )
 
 const methodWidth = 6
@@ -41,6 +43,8 @@ func MethodColor(method string) string {
 		return fmt.Sprintf("%s %s %s", bgCyan, padMethod(method), bgReset)
 	case "DELETE":
 		return fmt.Sprintf("%s %s %s", bgRed, padMethod(method), bgReset)
+	case "HEAD":
+		return fmt.Sprintf("%s %s %s", bgGray, padMethod(method), bgReset)
 	case "OPTIONS":
 		return fmt.Sprintf("%s %s %s", bgMagenta, padMethod(method), bgReset)
 	default:
@@ -68,10 +72,14 @@ func PrintList(reqs []RequestMeta) {
 	}
 
 	for _, r := range reqs {
+		file := r.FilePath
+		if r.Name != "" {
+			file += " > " + r.Name
+		}
 		fmt.Printf(
 			"%s  %-*s  %s\n", MethodColor(r.Method),
 			maxURL, r.URL,
-			r.FilePath,
+			file,
 		)
 	}
 }
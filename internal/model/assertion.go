@@ -21,11 +21,55 @@ const (
 )
 
 type PathSegment struct {
-	Key   *string
+	Key *string
+
+	// Index: [i], negative values count from the end
 	Index *int
+
+	// Wildcard: [*], selects every value
+	Wildcard bool
+
+	// Slice: [start:end], selects a range of an array
+	Slice *Slice
+
+	// Recursive: ..key, selects key at any depth
+	Recursive bool
+
+	// Filter: [?(@.role == "admin")], selects the matching elements
+	Filter *Filter
+}
+
+// Multi reports a segment that can select several values.
+func (s PathSegment) Multi() bool {
+	return s.Wildcard || s.Slice != nil || s.Recursive || s.Filter != nil
+}
+
+// Slice bounds are nil when omitted, negative ones count from the end.
+type Slice struct {
+	Start *int
+	End   *int
 }
 
+// Filter keeps the elements whose Path compares to Value with Op. An
+// empty Op only checks that Path exists.
+type Filter struct {
+	Path  []PathSegment
+	Op    string
+	Value Value
+}
+
+// Quantifiers apply an assertion over several values.
+const (
+	QuantifierAll   = "all"
+	QuantifierAny   = "any"
+	QuantifierNone  = "none"
+	QuantifierCount = "count"
+)
+
 type Assertion struct {
+	// Quantifier: optional all, any, none or count before the path
+	Quantifier string
+
 	// Path: dot-separated access to the response object, e.g. res.body.data.id
 	Path []PathSegment
 
@@ -36,6 +80,9 @@ type Assertion struct {
 	// For type checks like `is int`, this can be a string: "int", "json", "object"
 	Args []Value
 
+	// Source: the assertion as written, e.g. status eq 200
+	Source string
+
 	Line int
 }
 
@@ -56,9 +103,12 @@ func (a *Assertion) Clone() *Assertion {
 	pathCopy := append([]PathSegment{}, a.Path...)
 
 	return &Assertion{
-		Path: pathCopy,
-		Fn:   a.Fn,
-		Args: argsCopy,
-		Line: a.Line,
+		Quantifier: a.Quantifier,
+
+		Path:   pathCopy,
+		Fn:     a.Fn,
+		Args:   argsCopy,
+		Source: a.Source,
+		Line:   a.Line,
 	}
 }
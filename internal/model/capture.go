@@ -0,0 +1,19 @@
+package model
+
+type Capture struct {
+	// Name: context key the captured value is stored under, e.g. token
+	Name string
+
+	// Path: response path the value is read from, e.g. body.data.access_token
+	Path []PathSegment
+
+	Line int
+}
+
+func (c *Capture) Clone() *Capture {
+	return &Capture{
+		Name: c.Name,
+		Path: append([]PathSegment{}, c.Path...),
+		Line: c.Line,
+	}
+}
@@ -1,59 +1,28 @@
 package model
 
-import (
-	"github.com/Mahmoud-Khaled-FS/zyra/internal/utils"
-)
-
 type Line struct {
 	Text string
 	Num  int
 }
 
+// Pos is a 1-based line and column in a document.
+type Pos struct {
+	Line int
+	Col  int
+}
+
 type Document struct {
 	Lines      []Line
 	DocComment string
 
-	Method string
-	Path   string
+	// Depends: files that must run before this one, relative to this file
+	Depends []string
 
-	Headers map[string]string
-	Query   map[string]string
-	Vars    map[string]string
-	Body    string
-
-	Assertions []*Assertion
+	// Requests: one per request line, separated by ### lines
+	Requests []*Request
 }
 
 type Value struct {
 	Raw  any
 	Type string
 }
-
-func (d *Document) Clone() *Document {
-	cp := &Document{
-		DocComment: d.DocComment,
-		Method:     d.Method,
-		Path:       d.Path,
-		Body:       d.Body,
-		Headers:    utils.CloneMap(d.Headers),
-		Query:      utils.CloneMap(d.Query),
-	}
-
-	cp.Assertions = make([]*Assertion, len(d.Assertions))
-	for i, a := range d.Assertions {
-		cp.Assertions[i] = a.Clone()
-	}
-	return cp
-}
-
-func (d *Document) CloneAssertion() *Document {
-	cp := &Document{
-		DocComment: d.DocComment,
-		Method:     d.Method,
-		Path:       d.Path,
-		Body:       d.Body,
-		Headers:    utils.CloneMap(d.Headers),
-		Query:      utils.CloneMap(d.Query),
-	}
-	return cp
-}
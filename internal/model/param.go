@@ -0,0 +1,37 @@
+package model
+
+// Param is a single key = value entry of a section that keeps
+// declaration order and allows the same key more than once.
+type Param struct {
+	Key   string
+	Value string
+
+	// Pos: where the value starts
+	Pos Pos
+}
+
+func CloneParams(src []Param) []Param {
+	if src == nil {
+		return nil
+	}
+	return append([]Param{}, src...)
+}
+
+// Part is a [multipart] entry, a field value or, for `@path` entries,
+// a file sent with its content type.
+type Part struct {
+	Key   string
+	Value string
+	Pos   Pos
+
+	// File: Value is a file path
+	File bool
+	Type string
+}
+
+func CloneParts(src []Part) []Part {
+	if src == nil {
+		return nil
+	}
+	return append([]Part{}, src...)
+}
@@ -0,0 +1,111 @@
+package model
+
+import (
+	"maps"
+	"strings"
+
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/utils"
+)
+
+type Request struct {
+	// Name: optional, from the ### line opening the request
+	Name string
+	Line int
+
+	Method  string
+	Path    string
+	PathPos Pos
+
+	Headers []Param
+	Query   []Param
+	Vars    map[string]string
+	Options map[string]string
+
+	// OptionsPos: where each [options] value starts
+	OptionsPos map[string]Pos
+
+	Body    string
+	BodyPos Pos
+
+	// BodyFile is the file given by `< path` in [body], sent as is, or
+	// read and interpolated when BodyTemplate is set by `<@ path`.
+	BodyFile     string
+	BodyTemplate bool
+
+	// Form and Multipart are the [form] and [multipart] bodies.
+	Form      []Param
+	Multipart []Part
+
+	// GraphQL is set by the [graphql] sections.
+	GraphQL *GraphQL
+
+	Assertions []*Assertion
+	Captures   []*Capture
+}
+
+// GraphQL holds the [graphql] query and [graphql.variables] JSON.
+type GraphQL struct {
+	Query    string
+	QueryPos Pos
+
+	Variables    string
+	VariablesPos Pos
+}
+
+func NewRequest() *Request {
+	return &Request{
+		Vars:    make(map[string]string),
+		Options: make(map[string]string),
+
+		OptionsPos: make(map[string]Pos),
+	}
+}
+
+// HasBody reports a body from any source: [body] text or file, [form],
+// [multipart] or [graphql].
+func (r *Request) HasBody() bool {
+	return strings.TrimSpace(r.Body) != "" || r.BodyFile != "" ||
+		len(r.Form) > 0 || len(r.Multipart) > 0 || r.GraphQL != nil
+}
+
+func (r *Request) Clone() *Request {
+	cp := &Request{
+		Name:    r.Name,
+		Line:    r.Line,
+		Method:  r.Method,
+		Path:    r.Path,
+		PathPos: r.PathPos,
+		Body:    r.Body,
+		BodyPos: r.BodyPos,
+
+		BodyFile:     r.BodyFile,
+		BodyTemplate: r.BodyTemplate,
+
+		Headers: CloneParams(r.Headers),
+		Query:   CloneParams(r.Query),
+
+		Form:      CloneParams(r.Form),
+		Multipart: CloneParts(r.Multipart),
+
+		Vars:    utils.CloneMap(r.Vars),
+		Options: utils.CloneMap(r.Options),
+
+		OptionsPos: maps.Clone(r.OptionsPos),
+	}
+
+	if r.GraphQL != nil {
+		g := *r.GraphQL
+		cp.GraphQL = &g
+	}
+
+	cp.Assertions = make([]*Assertion, len(r.Assertions))
+	for i, a := range r.Assertions {
+		cp.Assertions[i] = a.Clone()
+	}
+
+	cp.Captures = make([]*Capture, len(r.Captures))
+	for i, c := range r.Captures {
+		cp.Captures[i] = c.Clone()
+	}
+	return cp
+}
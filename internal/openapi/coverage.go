@@ -0,0 +1,52 @@
+package openapi
+
+import (
+	"slices"
+	"sort"
+)
+
+// OperationCoverage lists the declared statuses of an operation that
+// responses did and did not exercise.
+type OperationCoverage struct {
+	Operation *Operation
+	Covered   []string
+	Missing   []string
+}
+
+func (s *Spec) record(op *Operation, status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.covered[coverageKey(op, status)] = true
+}
+
+// Coverage reports every operation, sorted by path then method.
+func (s *Spec) Coverage() []OperationCoverage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ops := slices.Clone(s.Operations)
+	sort.SliceStable(ops, func(i, j int) bool {
+		if ops[i].Path != ops[j].Path {
+			return ops[i].Path < ops[j].Path
+		}
+		return ops[i].Method < ops[j].Method
+	})
+
+	out := make([]OperationCoverage, 0, len(ops))
+	for _, op := range ops {
+		c := OperationCoverage{Operation: op}
+		for _, status := range op.Statuses {
+			if s.covered[coverageKey(op, status)] {
+				c.Covered = append(c.Covered, status)
+			} else {
+				c.Missing = append(c.Missing, status)
+			}
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+func coverageKey(op *Operation, status string) string {
+	return op.Method + " " + op.Path + " " + status
+}
@@ -0,0 +1,367 @@
+package openapi
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"gopkg.in/yaml.v3"
+)
+
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Spec is an OpenAPI 3 document used to check responses against their
+// declared operation.
+type Spec struct {
+	File       string
+	Operations []*Operation
+
+	// basePath is the path of the first server url, e.g. /v1.
+	basePath string
+	url      string
+
+	mu       sync.Mutex
+	compiler *jsonschema.Compiler
+	schemas  map[string]*jsonschema.Schema
+	covered  map[string]bool
+}
+
+// Operation is a method on a path template, e.g. GET /users/{id}.
+type Operation struct {
+	Method string
+	Path   string
+
+	// Statuses are the declared response codes, like 200, 4XX or default.
+	Statuses []string
+
+	pattern   *regexp.Regexp
+	params    int
+	responses map[string]*response
+}
+
+func (op *Operation) String() string {
+	return op.Method + " " + op.Path
+}
+
+type response struct {
+	headers map[string]header
+
+	// content maps media types to the pointer of their schema, empty
+	// when the media type has no schema.
+	content map[string]string
+}
+
+type header struct {
+	required bool
+	schema   string
+}
+
+// Load reads a YAML or JSON OpenAPI 3 document.
+func Load(path string) (*Spec, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(abs)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	doc, ok := normalize(raw).(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: not an OpenAPI document", path)
+	}
+
+	version, _ := doc["openapi"].(string)
+	if !strings.HasPrefix(version, "3.") {
+		return nil, fmt.Errorf("%s: only OpenAPI 3 is supported", path)
+	}
+	if strings.HasPrefix(version, "3.0") {
+		fromOpenAPI30(doc)
+	}
+
+	spec := &Spec{
+		File:     path,
+		url:      (&url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}).String(),
+		compiler: jsonschema.NewCompiler(),
+		schemas:  make(map[string]*jsonschema.Schema),
+		covered:  make(map[string]bool),
+		basePath: serverPath(doc),
+	}
+
+	spec.compiler.DefaultDraft(jsonschema.Draft2020)
+	if err := spec.compiler.AddResource(spec.url, doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if err := spec.readOperations(doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return spec, nil
+}
+
+func (s *Spec) readOperations(doc map[string]any) error {
+	paths, _ := doc["paths"].(map[string]any)
+
+	for path, item := range paths {
+		ops, _ := item.(map[string]any)
+		for _, method := range methods {
+			raw, ok := ops[method].(map[string]any)
+			if !ok {
+				continue
+			}
+
+			pattern, params, err := compileTemplate(path)
+			if err != nil {
+				return err
+			}
+
+			op := &Operation{
+				Method:    strings.ToUpper(method),
+				Path:      path,
+				pattern:   pattern,
+				params:    params,
+				responses: make(map[string]*response),
+			}
+
+			responses, _ := raw["responses"].(map[string]any)
+			for status, r := range responses {
+				pointer := "/paths/" + escape(path) + "/" + method + "/responses/" + escape(status)
+				op.responses[status] = readResponse(doc, r, pointer)
+				op.Statuses = append(op.Statuses, status)
+			}
+			sort.Strings(op.Statuses)
+
+			s.Operations = append(s.Operations, op)
+		}
+	}
+
+	// literal paths win over templated ones, e.g. /users/me over /users/{id}
+	sort.Slice(s.Operations, func(i, j int) bool {
+		a, b := s.Operations[i], s.Operations[j]
+		if a.params != b.params {
+			return a.params < b.params
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Method < b.Method
+	})
+	return nil
+}
+
+func readResponse(doc map[string]any, raw any, pointer string) *response {
+	r := &response{
+		headers: make(map[string]header),
+		content: make(map[string]string),
+	}
+
+	obj, pointer := deref(doc, raw, pointer)
+
+	headers, _ := obj["headers"].(map[string]any)
+	for name, h := range headers {
+		hobj, hpointer := deref(doc, h, pointer+"/headers/"+escape(name))
+		required, _ := hobj["required"].(bool)
+
+		var schema string
+		if _, ok := hobj["schema"]; ok {
+			schema = hpointer + "/schema"
+		}
+		r.headers[name] = header{required: required, schema: schema}
+	}
+
+	content, _ := obj["content"].(map[string]any)
+	for media, m := range content {
+		mobj, _ := m.(map[string]any)
+		if _, ok := mobj["schema"]; ok {
+			r.content[strings.ToLower(media)] = pointer + "/content/" + escape(media) + "/schema"
+		} else {
+			r.content[strings.ToLower(media)] = ""
+		}
+	}
+	return r
+}
+
+// deref follows a local $ref of a response or header object.
+func deref(doc map[string]any, raw any, pointer string) (map[string]any, string) {
+	obj, _ := raw.(map[string]any)
+	ref, ok := obj["$ref"].(string)
+	if !ok || !strings.HasPrefix(ref, "#/") {
+		return obj, pointer
+	}
+
+	var cur any = doc
+	for _, tok := range strings.Split(ref[2:], "/") {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return obj, pointer
+		}
+		cur = m[unescape(tok)]
+	}
+
+	target, _ := cur.(map[string]any)
+	return target, ref[1:]
+}
+
+// Match returns the operation for method and a request path.
+func (s *Spec) Match(method, path string) *Operation {
+	if s.basePath != "" {
+		path = strings.TrimPrefix(path, s.basePath)
+		if path == "" {
+			path = "/"
+		}
+	}
+
+	for _, op := range s.Operations {
+		if op.Method == strings.ToUpper(method) && op.pattern.MatchString(path) {
+			return op
+		}
+	}
+	return nil
+}
+
+func (s *Spec) schema(pointer string) (*jsonschema.Schema, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sch, ok := s.schemas[pointer]; ok {
+		return sch, nil
+	}
+
+	sch, err := s.compiler.Compile(s.url + "#" + pointer)
+	if err != nil {
+		return nil, err
+	}
+	s.schemas[pointer] = sch
+	return sch, nil
+}
+
+// compileTemplate turns /users/{id} into a pattern matching one
+// segment per parameter.
+func compileTemplate(path string) (*regexp.Regexp, int, error) {
+	var b strings.Builder
+	params := 0
+
+	b.WriteString("^")
+	for path != "" {
+		start := strings.IndexByte(path, '{')
+		if start == -1 {
+			b.WriteString(regexp.QuoteMeta(path))
+			break
+		}
+		end := strings.IndexByte(path[start:], '}')
+		if end == -1 {
+			return nil, 0, fmt.Errorf("invalid path template: %s", path)
+		}
+
+		b.WriteString(regexp.QuoteMeta(path[:start]))
+		b.WriteString("[^/]+")
+		params++
+		path = path[start+end+1:]
+	}
+	b.WriteString("/?$")
+
+	re, err := regexp.Compile(b.String())
+	return re, params, err
+}
+
+func serverPath(doc map[string]any) string {
+	servers, _ := doc["servers"].([]any)
+	if len(servers) == 0 {
+		return ""
+	}
+
+	server, _ := servers[0].(map[string]any)
+	raw, _ := server["url"].(string)
+	u, err := url.Parse(raw)
+	if err != nil || strings.Contains(u.Path, "{") {
+		return ""
+	}
+	return strings.TrimSuffix(u.Path, "/")
+}
+
+// normalize converts YAML maps to map[string]any, status codes written
+// as numbers become strings.
+func normalize(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, item := range v {
+			v[k] = normalize(item)
+		}
+		return v
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, item := range v {
+			m[fmt.Sprint(k)] = normalize(item)
+		}
+		return m
+	case []any:
+		for i, item := range v {
+			v[i] = normalize(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// fromOpenAPI30 rewrites the OpenAPI 3.0 schema keywords that differ
+// from JSON Schema 2020-12: nullable and boolean exclusive bounds.
+func fromOpenAPI30(v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		if nullable, _ := v["nullable"].(bool); nullable {
+			if t, ok := v["type"].(string); ok {
+				v["type"] = []any{t, "null"}
+			}
+		}
+		delete(v, "nullable")
+		exclusiveBound(v, "exclusiveMinimum", "minimum")
+		exclusiveBound(v, "exclusiveMaximum", "maximum")
+
+		for _, item := range v {
+			fromOpenAPI30(item)
+		}
+	case []any:
+		for _, item := range v {
+			fromOpenAPI30(item)
+		}
+	}
+}
+
+func exclusiveBound(v map[string]any, exclusive, bound string) {
+	flag, ok := v[exclusive].(bool)
+	if !ok {
+		return
+	}
+	delete(v, exclusive)
+	if flag {
+		if n, ok := v[bound]; ok {
+			v[exclusive] = n
+			delete(v, bound)
+		}
+	}
+}
+
+func escape(token string) string {
+	token = strings.ReplaceAll(token, "~", "~0")
+	token = strings.ReplaceAll(token, "/", "~1")
+	return url.PathEscape(token)
+}
+
+func unescape(token string) string {
+	token = strings.ReplaceAll(token, "~1", "/")
+	return strings.ReplaceAll(token, "~0", "~")
+}
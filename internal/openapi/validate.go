@@ -0,0 +1,178 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// Validate checks a response against the operation of method and path
+// and records the covered status. It returns the matched operation, nil
+// when the spec has none, and every violation found.
+func (s *Spec) Validate(method, path string, status int, headers http.Header, body any) (*Operation, []error) {
+	op := s.Match(method, path)
+	if op == nil {
+		return nil, []error{fmt.Errorf("no operation for %s %s", strings.ToUpper(method), path)}
+	}
+
+	code, resp := op.response(status)
+	if resp == nil {
+		return op, []error{fmt.Errorf("status %d is not declared", status)}
+	}
+	s.record(op, code)
+
+	var errs []error
+	errs = append(errs, s.validateHeaders(resp, headers)...)
+	errs = append(errs, s.validateBody(resp, headers, body)...)
+	return op, errs
+}
+
+// response finds the declared response for status: the exact code,
+// then its range like 2XX, then default.
+func (op *Operation) response(status int) (string, *response) {
+	code := strconv.Itoa(status)
+	candidates := []string{code, code[:1] + "XX", code[:1] + "xx", "default"}
+
+	for _, c := range candidates {
+		if r, ok := op.responses[c]; ok {
+			return c, r
+		}
+	}
+	return "", nil
+}
+
+func (s *Spec) validateHeaders(resp *response, headers http.Header) []error {
+	names := make([]string, 0, len(resp.headers))
+	for name := range resp.headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		h := resp.headers[name]
+		// Content-Type is described by content, not headers
+		if strings.EqualFold(name, "Content-Type") {
+			continue
+		}
+
+		values := headers.Values(name)
+		if len(values) == 0 {
+			if h.required {
+				errs = append(errs, fmt.Errorf("missing header %s", name))
+			}
+			continue
+		}
+		if h.schema == "" {
+			continue
+		}
+
+		if err := s.validateHeader(h.schema, values[0]); err != nil {
+			errs = append(errs, fmt.Errorf("header %s: %w", name, err))
+		}
+	}
+	return errs
+}
+
+// validateHeader checks a header value as a string, then as the JSON
+// value it holds, e.g. 42 for an integer schema.
+func (s *Spec) validateHeader(pointer, value string) error {
+	sch, err := s.schema(pointer)
+	if err != nil {
+		return err
+	}
+
+	err = sch.Validate(value)
+	if err == nil {
+		return nil
+	}
+
+	var typed any
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.UseNumber()
+	if decoder.Decode(&typed) == nil && sch.Validate(typed) == nil {
+		return nil
+	}
+	return schemaError(err)
+}
+
+func (s *Spec) validateBody(resp *response, headers http.Header, body any) []error {
+	if len(resp.content) == 0 {
+		return nil
+	}
+
+	contentType := headers.Get("Content-Type")
+	media, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return []error{fmt.Errorf("invalid content type %q", contentType)}
+	}
+
+	pointer, ok := resp.mediaSchema(media)
+	if !ok {
+		return []error{fmt.Errorf("content type %s is not declared", media)}
+	}
+	if pointer == "" {
+		return nil
+	}
+
+	sch, err := s.schema(pointer)
+	if err != nil {
+		return []error{err}
+	}
+	if err := sch.Validate(body); err != nil {
+		return []error{fmt.Errorf("body: %w", schemaError(err))}
+	}
+	return nil
+}
+
+// mediaSchema finds the schema for media: exact, then type/*, then */*.
+func (r *response) mediaSchema(media string) (string, bool) {
+	media = strings.ToLower(media)
+	major, _, _ := strings.Cut(media, "/")
+
+	for _, candidate := range []string{media, major + "/*", "*/*"} {
+		if pointer, ok := r.content[candidate]; ok {
+			return pointer, true
+		}
+	}
+	return "", false
+}
+
+// schemaError lists every failed keyword as "pointer: message",
+// leaving out the $ref and group errors that only wrap them.
+func schemaError(err error) error {
+	var ve *jsonschema.ValidationError
+	if !errors.As(err, &ve) {
+		return err
+	}
+
+	var out []string
+	for _, leaf := range leaves(ve) {
+		unit := leaf.BasicOutput()
+		pointer := unit.InstanceLocation
+		if pointer == "" {
+			pointer = "/"
+		}
+		out = append(out, pointer+": "+unit.Error.String())
+	}
+	return errors.New(strings.Join(out, "; "))
+}
+
+func leaves(ve *jsonschema.ValidationError) []*jsonschema.ValidationError {
+	if len(ve.Causes) == 0 {
+		return []*jsonschema.ValidationError{ve}
+	}
+
+	var out []*jsonschema.ValidationError
+	for _, cause := range ve.Causes {
+		out = append(out, leaves(cause)...)
+	}
+	return out
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Mahmoud-Khaled-FS/zyra/internal/model"
 )
@@ -15,80 +16,59 @@ func ParseAssertionLine(line string, lineNum int) (*model.Assertion, error) {
 	}
 
 	tokens := tokenizeAssertion(line)
+
+	var quantifier string
+	if len(tokens) > 2 && isQuantifier(tokens[0]) {
+		quantifier = tokens[0]
+		tokens = tokens[1:]
+	}
+
 	if len(tokens) < 2 {
 		return nil, fmt.Errorf("invalid assertion syntax: %s", line)
 	}
 
-	path := parsePath(tokens[0])
+	path, err := parsePath(tokens[0])
+	if err != nil {
+		return nil, err
+	}
 	fn := tokens[1]
 
+	timing := isTimingPath(path)
 	args := make([]model.Value, 0, len(tokens)-2)
 	for _, t := range tokens[2:] {
+		if timing {
+			if d, err := time.ParseDuration(t); err == nil {
+				args = append(args, model.Value{Raw: d, Type: "duration"})
+				continue
+			}
+		}
 		args = append(args, parseValue(t))
 	}
 
 	return &model.Assertion{
-		Path: path,
-		Fn:   fn,
-		Args: args,
-		Line: lineNum,
+		Quantifier: quantifier,
+
+		Path:   path,
+		Fn:     fn,
+		Args:   args,
+		Source: line,
+		Line:   lineNum,
 	}, nil
 }
 
-func parsePath(path string) []model.PathSegment {
-	var segments []model.PathSegment
-	var buf strings.Builder
-	inBracket := false
-	inQuotes := false
-
-	flushKey := func() {
-		if buf.Len() == 0 {
-			return
-		}
-		s := buf.String()
-		buf.Reset()
-		segments = append(segments, model.PathSegment{Key: &s})
-	}
-
-	for i := 0; i < len(path); i++ {
-		c := path[i]
-
-		switch c {
-		case '.':
-			if !inBracket {
-				flushKey()
-				continue
-			}
-			buf.WriteByte(c)
-
-		case '[':
-			flushKey()
-			inBracket = true
-
-		case ']':
-			val := buf.String()
-			buf.Reset()
-			inBracket = false
-
-			// index [0]
-			if idx, err := strconv.Atoi(val); err == nil {
-				segments = append(segments, model.PathSegment{Index: &idx})
-			} else {
-				// string key ["foo"]
-				val = strings.Trim(val, `"`)
-				segments = append(segments, model.PathSegment{Key: &val})
-			}
-
-		case '"':
-			inQuotes = !inQuotes
+// isTimingPath reports a path under the timing root, the only one whose
+// arguments read duration literals like 500ms.
+func isTimingPath(path []model.PathSegment) bool {
+	return len(path) > 0 && path[0].Key != nil && strings.EqualFold(*path[0].Key, "timing")
+}
 
-		default:
-			buf.WriteByte(c)
-		}
+func isQuantifier(s string) bool {
+	switch s {
+	case model.QuantifierAll, model.QuantifierAny, model.QuantifierNone, model.QuantifierCount:
+		return true
+	default:
+		return false
 	}
-
-	flushKey()
-	return segments
 }
 
 func tokenizeAssertion(s string) []string {
@@ -96,11 +76,20 @@ func tokenizeAssertion(s string) []string {
 	var buf strings.Builder
 	inQuotes := false
 	brackets := 0
+	braces := 0
 
 	for i := 0; i < len(s); i++ {
 		c := s[i]
 
 		switch c {
+		case '{':
+			braces++
+			buf.WriteByte(c)
+
+		case '}':
+			braces--
+			buf.WriteByte(c)
+
 		case '"':
 			inQuotes = !inQuotes
 			buf.WriteByte(c)
@@ -114,7 +103,7 @@ func tokenizeAssertion(s string) []string {
 			buf.WriteByte(c)
 
 		case ' ', '\t':
-			if inQuotes || brackets > 0 {
+			if inQuotes || brackets > 0 || braces > 0 {
 				buf.WriteByte(c)
 			} else if buf.Len() > 0 {
 				tokens = append(tokens, buf.String())
@@ -156,8 +145,10 @@ func parseValue(v string) model.Value {
 		return model.Value{Raw: v == "true", Type: "bool"}
 	}
 
-	if strings.HasPrefix(v, "body") || strings.HasPrefix(v, "status") || strings.HasPrefix(v, "headers") {
-		return model.Value{Raw: parsePath(v), Type: "ID"}
+	if strings.HasPrefix(v, "body") || strings.HasPrefix(v, "status") || strings.HasPrefix(v, "headers") || strings.HasPrefix(v, "cookies") || strings.HasPrefix(v, "timing") {
+		if path, err := parsePath(v); err == nil {
+			return model.Value{Raw: path, Type: "ID"}
+		}
 	}
 
 	if strings.HasPrefix(v, "{{") && strings.HasSuffix(v, "}}") {
@@ -1,15 +1,31 @@
 package parser
 
 import (
+	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/Mahmoud-Khaled-FS/zyra/internal/model"
 )
 
 type Config struct {
+	// Dir: directory the config was loaded from, relative option paths resolve against it
+	Dir string
+
 	Context    map[string]string
 	Options    map[string]string
 	Assertions []*model.Assertion
+
+	// Profiles: named overlays declared as [context.name] and [options.name]
+	Profiles map[string]*Profile
+
+	// Profile: the profile applied with UseProfile, empty for none
+	Profile string
+}
+
+type Profile struct {
+	Context map[string]string
+	Options map[string]string
 }
 
 func ParseConfig(src string) (*Config, error) {
@@ -18,8 +34,9 @@ func ParseConfig(src string) (*Config, error) {
 	p := &parser{
 		lines: lines,
 		config: &Config{
-			Context: make(map[string]string),
-			Options: make(map[string]string),
+			Context:  make(map[string]string),
+			Options:  make(map[string]string),
+			Profiles: make(map[string]*Profile),
 		},
 	}
 
@@ -35,7 +52,7 @@ func (p *parser) parseConfig() error {
 		line := strings.TrimSpace(p.current().Text)
 
 		switch {
-		case line == "":
+		case line == "" || strings.HasPrefix(line, "#"):
 			p.pos++
 
 		case isSection(line):
@@ -54,6 +71,10 @@ func (p *parser) parseConfigSection() error {
 	section := strings.ToLower(strings.Trim(p.current().Text, "[]"))
 	p.pos++
 
+	if base, name, ok := strings.Cut(section, "."); ok {
+		return p.parseProfileSection(base, name)
+	}
+
 	switch section {
 	case "context":
 		return p.parseKeyValueSection(p.config.Context)
@@ -68,3 +89,62 @@ func (p *parser) parseConfigSection() error {
 		return p.error("unknown section: " + section)
 	}
 }
+
+func (p *parser) parseProfileSection(section, name string) error {
+	if name == "" {
+		return p.error("missing profile name: " + section)
+	}
+
+	profile, ok := p.config.Profiles[name]
+	if !ok {
+		profile = &Profile{
+			Context: make(map[string]string),
+			Options: make(map[string]string),
+		}
+		p.config.Profiles[name] = profile
+	}
+
+	switch section {
+	case "context":
+		return p.parseKeyValueSection(profile.Context)
+
+	case "options":
+		return p.parseKeyValueSection(profile.Options)
+
+	default:
+		return p.error("unknown section: " + section + "." + name)
+	}
+}
+
+// ProfileNames returns the declared profiles in alphabetical order.
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// UseProfile overlays the named profile on the base [context] and
+// [options] sections. An empty name keeps the base config.
+func (c *Config) UseProfile(name string) error {
+	if name == "" {
+		return nil
+	}
+
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return fmt.Errorf("unknown env: %s (available: %s)", name, strings.Join(c.ProfileNames(), ", "))
+	}
+
+	for k, v := range profile.Context {
+		c.Context[k] = v
+	}
+	for k, v := range profile.Options {
+		c.Options[k] = v
+	}
+
+	c.Profile = name
+	return nil
+}
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/Mahmoud-Khaled-FS/zyra/internal/model"
@@ -12,10 +13,7 @@ func ParseDocument(src string) (*model.Document, error) {
 	p := &parser{
 		lines: lines,
 		doc: &model.Document{
-			Headers: make(map[string]string),
-			Query:   make(map[string]string),
-			Vars:    make(map[string]string),
-			Lines:   lines,
+			Lines: lines,
 		},
 	}
 
@@ -23,6 +21,10 @@ func ParseDocument(src string) (*model.Document, error) {
 		return nil, err
 	}
 
+	if err := p.validateRequests(); err != nil {
+		return nil, err
+	}
+
 	return p.doc, nil
 }
 
@@ -30,15 +32,17 @@ func (p *parser) parseDocument() error {
 	for p.pos < len(p.lines) {
 		line := strings.TrimSpace(p.current().Text)
 
-		// Comment Line
-		if strings.HasPrefix(line, "#") {
-			continue
-		}
-
 		switch {
 		case line == "":
 			p.pos++
 
+		case isDelimiter(line):
+			p.parseDelimiter()
+
+		// Comment Line
+		case strings.HasPrefix(line, "#"):
+			p.pos++
+
 		case line == `"""`:
 			if err := p.parseDocComment(); err != nil {
 				return err
@@ -78,6 +82,65 @@ func (p *parser) parseDocComment() error {
 	return nil
 }
 
+// parseDelimiter closes the current request. Text after ### names the
+// next one.
+func (p *parser) parseDelimiter() {
+	line := strings.TrimSpace(p.current().Text)
+	p.name = strings.TrimSpace(strings.TrimLeft(line, "#"))
+	p.req = nil
+	p.pos++
+}
+
+// request returns the request sections belong to, opening a new one
+// after a ### delimiter.
+func (p *parser) request() *model.Request {
+	if p.req == nil {
+		p.req = model.NewRequest()
+		p.req.Name = p.name
+		p.req.Line = p.current().Num
+		p.name = ""
+		p.doc.Requests = append(p.doc.Requests, p.req)
+	}
+	return p.req
+}
+
+func (p *parser) validateRequests() error {
+	if len(p.doc.Requests) == 0 {
+		return fmt.Errorf("no request line found")
+	}
+
+	for _, r := range p.doc.Requests {
+		if r.Method == "" {
+			return fmt.Errorf("line %d: missing request line", r.Line)
+		}
+		if bodies(r) > 1 {
+			return fmt.Errorf("line %d: use only one of [body], [form], [multipart] and [graphql]", r.Line)
+		}
+		if r.GraphQL != nil && strings.TrimSpace(r.GraphQL.Query) == "" {
+			return fmt.Errorf("line %d: [graphql] query is missing", r.Line)
+		}
+	}
+	return nil
+}
+
+// bodies counts the body sections of r.
+func bodies(r *model.Request) int {
+	n := 0
+	if strings.TrimSpace(r.Body) != "" || r.BodyFile != "" {
+		n++
+	}
+	if len(r.Form) > 0 {
+		n++
+	}
+	if len(r.Multipart) > 0 {
+		n++
+	}
+	if r.GraphQL != nil {
+		n++
+	}
+	return n
+}
+
 func (p *parser) parseRequestLine() error {
 	line := p.current().Text
 	parts := strings.Fields(line)
@@ -86,8 +149,20 @@ func (p *parser) parseRequestLine() error {
 		return p.error("invalid request line")
 	}
 
-	p.doc.Method = parts[0]
-	p.doc.Path = parts[1]
+	if p.req != nil && p.req.Method != "" {
+		return p.error("expected ### before the next request")
+	}
+
+	req := p.request()
+	req.Line = p.current().Num
+	req.Method = parts[0]
+	req.Path = parts[1]
+
+	method := strings.Index(line, parts[0]) + len(parts[0])
+	req.PathPos = model.Pos{
+		Line: p.current().Num,
+		Col:  method + strings.Index(line[method:], parts[1]) + 1,
+	}
 
 	p.pos++
 	return nil
@@ -95,23 +170,48 @@ func (p *parser) parseRequestLine() error {
 
 func (p *parser) parseDocumentSection() error {
 	section := strings.ToLower(strings.Trim(p.current().Text, "[]"))
+
+	if section == "meta" {
+		p.pos++
+		return p.parseMetaSection()
+	}
+
+	req := p.request()
 	p.pos++
 
 	switch section {
 	case "headers":
-		return p.parseKeyValueSection(p.doc.Headers)
+		return p.parseParamSection(&req.Headers)
 
 	case "query":
-		return p.parseKeyValueSection(p.doc.Query)
+		return p.parseParamSection(&req.Query)
 
 	case "body":
 		return p.parseBody()
 
+	case "form":
+		return p.parseParamSection(&req.Form)
+
+	case "multipart":
+		return p.parseMultipartSection()
+
+	case "graphql":
+		return p.parseGraphQL(false)
+
+	case "graphql.variables":
+		return p.parseGraphQL(true)
+
 	case "assert":
 		return p.parseAssertSection()
 
 	case "vars":
-		return p.parseKeyValueSection(p.doc.Vars)
+		return p.parseKeyValueSection(req.Vars)
+
+	case "options":
+		return p.parseKeyValuePos(req.Options, req.OptionsPos)
+
+	case "capture":
+		return p.parseCaptureSection()
 
 	default:
 		return p.error("unknown section: " + section)
@@ -124,14 +224,34 @@ func (p *parser) parseAssertSection() error {
 		line := strings.TrimSpace(p.current().Text)
 
 		switch {
+		case isBlockEnd(line):
+			return nil
+
 		case line == "" || strings.HasPrefix(line, "#"):
 			p.pos++
 
-		case isSection(line):
+		default:
+			if err := p.parseAssertionLine(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (p *parser) parseCaptureSection() error {
+	for p.pos < len(p.lines) {
+		line := strings.TrimSpace(p.current().Text)
+
+		switch {
+		case isBlockEnd(line):
 			return nil
 
+		case line == "" || strings.HasPrefix(line, "#"):
+			p.pos++
+
 		default:
-			if err := p.parseAssertionLine(); err != nil {
+			if err := p.parseCaptureLine(); err != nil {
 				return err
 			}
 		}
@@ -167,7 +287,36 @@ func (p *parser) parseAssertionLine() error {
 		return p.error(err.Error())
 	}
 
-	p.doc.Assertions = append(p.doc.Assertions, assertion)
+	p.req.Assertions = append(p.req.Assertions, assertion)
+
+	p.pos++
+	return nil
+}
+
+func (p *parser) parseCaptureLine() error {
+	line := strings.TrimSpace(p.current().Text)
+
+	name, path, ok := strings.Cut(line, "=")
+	if !ok {
+		return p.error("expected name = path")
+	}
+
+	name = strings.TrimSpace(name)
+	path = strings.TrimSpace(path)
+	if name == "" || path == "" {
+		return p.error("expected name = path")
+	}
+
+	segments, err := parsePath(path)
+	if err != nil {
+		return p.error(err.Error())
+	}
+
+	p.req.Captures = append(p.req.Captures, &model.Capture{
+		Name: name,
+		Path: segments,
+		Line: p.current().Num,
+	})
 
 	p.pos++
 	return nil
@@ -187,15 +336,58 @@ func (p *parser) parseConfigAssertionLine() error {
 	return nil
 }
 
+// isDelimiter reports a ### line separating requests, ### alone or
+// followed by a space and a name. ####, ###notes and the like are
+// comments.
+func isDelimiter(line string) bool {
+	rest, ok := strings.CutPrefix(line, "###")
+	return ok && (rest == "" || rest[0] == ' ' || rest[0] == '\t')
+}
+
+// isBlockEnd reports a line that ends the current section: a section
+// header, a ### delimiter or the request line of the next request,
+// which then fails for the missing ###.
+func isBlockEnd(line string) bool {
+	return isSection(line) || isDelimiter(line) || isNextRequest(line)
+}
+
+// isNextRequest reports a request line with a target, so body text like
+// "Delete the draft" does not end a section.
+func isNextRequest(line string) bool {
+	fields := strings.Fields(line)
+	return isRequestLine(line) && len(fields) > 1 && isRequestTarget(fields[1])
+}
+
+// isRequestLine reports a line starting with a standard HTTP method, in
+// any case, or an uppercase token like PROPFIND for extension methods.
 func isRequestLine(line string) bool {
 	if line == "" {
 		return false
 	}
-	method := strings.Fields(line)[0]
-	switch strings.ToUpper(method) {
-	case "GET", "POST", "PUT", "PATCH", "DELETE":
+	fields := strings.Fields(line)
+	switch strings.ToUpper(fields[0]) {
+	case "GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS", "TRACE", "CONNECT":
 		return true
 	default:
-		return false
+		// extension methods need a target, so TODO or NOTE lines do
+		// not start a request
+		return isMethodToken(fields[0]) && len(fields) > 1 && isRequestTarget(fields[1])
+	}
+}
+
+// isRequestTarget reports a path, an absolute URL or a template for one.
+func isRequestTarget(s string) bool {
+	return strings.HasPrefix(s, "/") || strings.HasPrefix(s, "{{") || strings.Contains(s, "://") || s == "*"
+}
+
+func isMethodToken(s string) bool {
+	for i, c := range s {
+		switch {
+		case c >= 'A' && c <= 'Z':
+		case i > 0 && (c >= '0' && c <= '9' || c == '-' || c == '_'):
+		default:
+			return false
+		}
 	}
+	return true
 }
@@ -0,0 +1,115 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseDocumentMethods(t *testing.T) {
+	methods := []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS", "TRACE", "CONNECT", "PROPFIND", "head"}
+
+	for _, method := range methods {
+		doc, err := ParseDocument(method + " /users\n")
+		if err != nil {
+			t.Fatalf("%s: %v", method, err)
+		}
+		if got := doc.Requests[0].Method; got != method {
+			t.Errorf("method = %q, want %q", got, method)
+		}
+	}
+}
+
+func TestIsRequestLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want bool
+	}{
+		{"HEAD /users", true},
+		{"options /users", true},
+		{"MKCOL /files", true},
+		{"VERSION-CONTROL /files", true},
+		{"PROPFIND {{BASE}}/files", true},
+		{"Propfind /files", false},
+		{"TODO", false},
+		{"NOTE check the totals", false},
+		{"WARNING: flaky", false},
+		{"status eq 200", false},
+		{"[assert]", false},
+	}
+
+	for _, tt := range tests {
+		if got := isRequestLine(tt.line); got != tt.want {
+			t.Errorf("isRequestLine(%q) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestIsDelimiter(t *testing.T) {
+	tests := []struct {
+		line string
+		want bool
+	}{
+		{"###", true},
+		{"### create user", true},
+		{"###\tcreate", true},
+		{"#### section", false},
+		{"###notes", false},
+		{"# comment", false},
+	}
+
+	for _, tt := range tests {
+		if got := isDelimiter(tt.line); got != tt.want {
+			t.Errorf("isDelimiter(%q) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestParseDocumentMissingDelimiter(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"adjacent", "GET /a\nGET /b\n", "line 2: expected ### before the next request"},
+		{"after body", "POST /a\n[body]\n{\"a\":1}\nGET /b\n", "line 4: expected ### before the next request"},
+		{"after assert", "GET /a\n\n[assert]\nstatus eq 200\n\nDELETE /b\n", "line 6: expected ### before the next request"},
+		{"after headers", "GET /a\n[headers]\nAccept = text/plain\nPUT {{BASE}}/b\n", "line 4: expected ### before the next request"},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseDocument(tt.src)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("%s: err = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestParseDocumentBodyText(t *testing.T) {
+	doc, err := ParseDocument("POST /notes\n\n[body]\nDelete the draft\nget ready\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := doc.Requests[0].Body, "Delete the draft\nget ready\n"; got != want {
+		t.Errorf("body = %q, want %q", got, want)
+	}
+}
+
+func TestParseDocumentMeta(t *testing.T) {
+	sources := map[string]string{
+		"before": "[meta]\ndepends = login.zyra, users/create.zyra\n\nGET /users/me\n",
+		"after":  "GET /users/me\n\n[meta]\ndepends = login.zyra, users/create.zyra\n",
+	}
+
+	for name, src := range sources {
+		doc, err := ParseDocument(src)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if got := strings.Join(doc.Depends, ","); got != "login.zyra,users/create.zyra" {
+			t.Errorf("%s: depends = %q", name, got)
+		}
+		if len(doc.Requests) != 1 || doc.Requests[0].Path != "/users/me" {
+			t.Errorf("%s: requests = %+v", name, doc.Requests)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseDotEnv reads KEY=VALUE lines of a .env file. Blank lines, # comments
+// and an optional `export` prefix are allowed. Double-quoted values
+// support escapes, single-quoted values are kept as is.
+func ParseDotEnv(src string) (map[string]string, error) {
+	env := make(map[string]string)
+
+	for _, l := range splitLines(src) {
+		line := strings.TrimSpace(l.Text)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+
+		key, val, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", l.Num)
+		}
+
+		val, err := parseDotEnvValue(strings.TrimSpace(val))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", l.Num, err)
+		}
+
+		env[key] = val
+	}
+
+	return env, nil
+}
+
+func parseDotEnvValue(val string) (string, error) {
+	switch {
+	case strings.HasPrefix(val, `"`):
+		end := strings.LastIndex(val, `"`)
+		if end == 0 {
+			return "", fmt.Errorf("unterminated quoted value")
+		}
+		return strconv.Unquote(val[:end+1])
+
+	case strings.HasPrefix(val, "'"):
+		end := strings.LastIndex(val, "'")
+		if end == 0 {
+			return "", fmt.Errorf("unterminated quoted value")
+		}
+		return val[1:end], nil
+
+	default:
+		// strip inline comments: KEY=value # comment
+		if i := strings.Index(val, " #"); i != -1 {
+			val = strings.TrimSpace(val[:i])
+		}
+		return val, nil
+	}
+}
@@ -0,0 +1,64 @@
+package parser
+
+import (
+	"maps"
+	"strings"
+	"testing"
+)
+
+func TestParseDotEnv(t *testing.T) {
+	src := strings.Join([]string{
+		"# database",
+		"",
+		"HOST=localhost",
+		"  PORT = 5432  ",
+		"export TOKEN=abc",
+		`DOUBLE="a \"quoted\" value\n"`,
+		`SINGLE='raw \n # kept'`,
+		`HASH="a # b"`,
+		"INLINE=value # comment",
+		"URL=http://x/#frag",
+		"EMPTY=",
+		"EQUALS=a=b",
+	}, "\n")
+
+	got, err := ParseDotEnv(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"HOST":   "localhost",
+		"PORT":   "5432",
+		"TOKEN":  "abc",
+		"DOUBLE": "a \"quoted\" value\n",
+		"SINGLE": `raw \n # kept`,
+		"HASH":   "a # b",
+		"INLINE": "value",
+		"URL":    "http://x/#frag",
+		"EMPTY":  "",
+		"EQUALS": "a=b",
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("ParseDotEnv = %v, want %v", got, want)
+	}
+}
+
+func TestParseDotEnvErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"HOST", "line 1: expected KEY=VALUE"},
+		{"A=1\n=2", "line 2: expected KEY=VALUE"},
+		{`A="open`, "line 1: unterminated quoted value"},
+		{"A='open", "line 1: unterminated quoted value"},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseDotEnv(tt.src)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("ParseDotEnv(%q) err = %v, want %q", tt.src, err, tt.want)
+		}
+	}
+}
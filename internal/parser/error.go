@@ -3,5 +3,10 @@ package parser
 import "fmt"
 
 func (p *parser) error(msg string) error {
-	return fmt.Errorf("line %d: %s", p.current().Num, msg)
+	return p.errorAt(p.current().Num, msg)
+}
+
+// errorAt is error for a line parsed earlier than the current one.
+func (p *parser) errorAt(line int, msg string) error {
+	return fmt.Errorf("line %d: %s", line, msg)
 }
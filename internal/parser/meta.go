@@ -0,0 +1,40 @@
+package parser
+
+import "strings"
+
+func (p *parser) parseMetaSection() error {
+	for p.pos < len(p.lines) {
+		line := strings.TrimSpace(p.current().Text)
+
+		if isBlockEnd(line) {
+			return nil
+		}
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			p.pos++
+			continue
+		}
+
+		key, val, ok := strings.Cut(line, "=")
+		if !ok {
+			return p.error("expected key = value")
+		}
+
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "depends", "needs":
+			for _, dep := range strings.Split(val, ",") {
+				dep = strings.TrimSpace(dep)
+				if dep == "" {
+					continue
+				}
+				p.doc.Depends = append(p.doc.Depends, dep)
+			}
+
+		default:
+			return p.error("unknown meta key: " + strings.TrimSpace(key))
+		}
+
+		p.pos++
+	}
+	return nil
+}
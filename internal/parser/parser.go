@@ -11,6 +11,10 @@ type parser struct {
 	pos    int
 	doc    *model.Document
 	config *Config
+
+	// req is the request being parsed, name the name for the next one.
+	req  *model.Request
+	name string
 }
 
 func (p *parser) current() model.Line {
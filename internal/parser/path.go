@@ -0,0 +1,280 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/model"
+)
+
+// filterOps are the comparisons allowed in [?(...)], longest first.
+var filterOps = []string{"==", "!=", ">=", "<=", ">", "<"}
+
+// pathScanner reads assertion and capture paths:
+//
+//	body.user.id        keys
+//	body["a.b"]         quoted key
+//	body.items[0]       index, [-1] is the last element
+//	body.items[*]       every element
+//	body.items[1:3]     slice, bounds are optional
+//	body..id            id at any depth
+//	body.users[?(@.role == "admin")]  elements matching a filter
+type pathScanner struct {
+	src string
+	pos int
+}
+
+// ParsePath reads a response path such as body.items[0].id.
+func ParsePath(path string) ([]model.PathSegment, error) {
+	return parsePath(path)
+}
+
+func parsePath(path string) ([]model.PathSegment, error) {
+	s := &pathScanner{src: path}
+
+	segments, err := s.segments(false)
+	if err != nil {
+		return nil, err
+	}
+	if s.pos < len(s.src) {
+		return nil, s.errorf("unexpected %q", s.src[s.pos])
+	}
+	return segments, nil
+}
+
+// segments reads segments up to the end of the path, or of the filter
+// path when inFilter is set.
+func (s *pathScanner) segments(inFilter bool) ([]model.PathSegment, error) {
+	var segments []model.PathSegment
+
+	// the root has no leading dot
+	if !inFilter && s.pos < len(s.src) && s.peek() != '[' && s.peek() != '.' {
+		key := s.key(inFilter)
+		segments = append(segments, model.PathSegment{Key: &key})
+	}
+
+	for s.pos < len(s.src) {
+		switch s.peek() {
+		case '.':
+			s.pos++
+			recursive := false
+			if s.peek() == '.' {
+				s.pos++
+				recursive = true
+			}
+
+			if s.peek() == '*' {
+				s.pos++
+				segments = append(segments, model.PathSegment{Wildcard: true, Recursive: recursive})
+				continue
+			}
+
+			key := s.key(inFilter)
+			if key == "" {
+				return nil, s.errorf("expected key")
+			}
+			segments = append(segments, model.PathSegment{Key: &key, Recursive: recursive})
+
+		case '[':
+			s.pos++
+			seg, err := s.bracket(inFilter)
+			if err != nil {
+				return nil, err
+			}
+			segments = append(segments, seg)
+
+		default:
+			if inFilter {
+				return segments, nil
+			}
+			return nil, s.errorf("unexpected %q", s.peek())
+		}
+	}
+	return segments, nil
+}
+
+// key reads an unquoted key. Keys stop at . and [, and inside filters
+// also at spaces, comparisons and the closing parenthesis.
+func (s *pathScanner) key(inFilter bool) string {
+	start := s.pos
+	for s.pos < len(s.src) {
+		c := s.peek()
+		if c == '.' || c == '[' || c == ']' {
+			break
+		}
+		if inFilter && strings.ContainsRune(" \t=!<>)", rune(c)) {
+			break
+		}
+		s.pos++
+	}
+	return s.src[start:s.pos]
+}
+
+// bracket reads the content of [...], after the opening bracket.
+func (s *pathScanner) bracket(inFilter bool) (model.PathSegment, error) {
+	var seg model.PathSegment
+
+	switch {
+	case s.peek() == '*':
+		s.pos++
+		seg.Wildcard = true
+
+	case s.peek() == '"':
+		key, err := s.quoted()
+		if err != nil {
+			return seg, err
+		}
+		seg.Key = &key
+
+	case strings.HasPrefix(s.src[s.pos:], "?("):
+		if inFilter {
+			return seg, s.errorf("nested filters are not supported")
+		}
+		s.pos += 2
+		filter, err := s.filter()
+		if err != nil {
+			return seg, err
+		}
+		seg.Filter = filter
+
+	default:
+		end := strings.IndexByte(s.src[s.pos:], ']')
+		if end == -1 {
+			return seg, s.errorf("missing ]")
+		}
+		raw := s.src[s.pos : s.pos+end]
+
+		var err error
+		if strings.Contains(raw, ":") {
+			seg.Slice, err = parseSlice(raw)
+		} else {
+			var idx int
+			idx, err = strconv.Atoi(strings.TrimSpace(raw))
+			seg.Index = &idx
+		}
+		if err != nil {
+			return seg, s.errorf("invalid index [%s]", raw)
+		}
+		s.pos += end
+	}
+
+	if s.peek() != ']' {
+		return seg, s.errorf("missing ]")
+	}
+	s.pos++
+	return seg, nil
+}
+
+func (s *pathScanner) quoted() (string, error) {
+	s.pos++ // opening quote
+	end := strings.IndexByte(s.src[s.pos:], '"')
+	if end == -1 {
+		return "", s.errorf("unterminated string")
+	}
+	key := s.src[s.pos : s.pos+end]
+	s.pos += end + 1
+	return key, nil
+}
+
+// filter reads `@.path op value)` after the opening `?(`.
+func (s *pathScanner) filter() (*model.Filter, error) {
+	s.skipSpaces()
+	if s.peek() != '@' {
+		return nil, s.errorf("filter must start with @")
+	}
+	s.pos++
+
+	path, err := s.segments(true)
+	if err != nil {
+		return nil, err
+	}
+	filter := &model.Filter{Path: path}
+
+	s.skipSpaces()
+	for _, op := range filterOps {
+		if strings.HasPrefix(s.src[s.pos:], op) {
+			filter.Op = op
+			s.pos += len(op)
+			break
+		}
+	}
+
+	if filter.Op != "" {
+		s.skipSpaces()
+		raw, err := s.filterValue()
+		if err != nil {
+			return nil, err
+		}
+		filter.Value = parseValue(raw)
+	}
+
+	s.skipSpaces()
+	if s.peek() != ')' {
+		return nil, s.errorf("missing )")
+	}
+	s.pos++
+	return filter, nil
+}
+
+// filterValue reads the right side of a filter comparison.
+func (s *pathScanner) filterValue() (string, error) {
+	start := s.pos
+	if s.peek() == '"' {
+		if _, err := s.quoted(); err != nil {
+			return "", err
+		}
+		return s.src[start:s.pos], nil
+	}
+
+	for s.pos < len(s.src) && s.peek() != ')' && s.peek() != ' ' {
+		s.pos++
+	}
+	if s.pos == start {
+		return "", s.errorf("expected value")
+	}
+	return s.src[start:s.pos], nil
+}
+
+func parseSlice(raw string) (*model.Slice, error) {
+	from, to, _ := strings.Cut(raw, ":")
+
+	bound := func(v string) (*int, error) {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			return nil, nil
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, err
+		}
+		return &n, nil
+	}
+
+	start, err := bound(from)
+	if err != nil {
+		return nil, err
+	}
+	end, err := bound(to)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Slice{Start: start, End: end}, nil
+}
+
+func (s *pathScanner) peek() byte {
+	if s.pos >= len(s.src) {
+		return 0
+	}
+	return s.src[s.pos]
+}
+
+func (s *pathScanner) skipSpaces() {
+	for s.peek() == ' ' || s.peek() == '\t' {
+		s.pos++
+	}
+}
+
+func (s *pathScanner) errorf(format string, args ...any) error {
+	return fmt.Errorf("invalid path %s: %s at col %d", s.src, fmt.Sprintf(format, args...), s.pos+1)
+}
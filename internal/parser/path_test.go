@@ -0,0 +1,113 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/model"
+)
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"body", "body"},
+		{"body.user.id", "body.user.id"},
+		{`body["a.b"].c`, `body."a.b".c`},
+		{`headers["Content-Type"]`, `headers."Content-Type"`},
+		{"body.items[0]", "body.items.[0]"},
+		{"body.items[-1].id", "body.items.[-1].id"},
+		{"body.items[*].id", "body.items.[*].id"},
+		{"body.*", "body.[*]"},
+		{"body.items[1:3]", "body.items.[1:3]"},
+		{"body.items[:2]", "body.items.[:2]"},
+		{"body.items[-2:]", "body.items.[-2:]"},
+		{"body..id", "body..id"},
+		{"body..*", "body..[*]"},
+		{`body.users[?(@.role == "admin")].name`, `body.users.[?(role == string:admin)].name`},
+		{"body.users[?(@.age >= 18)]", "body.users.[?(age >= int:18)]"},
+		{"body.users[?(@.tags[0] != 1.5)]", "body.users.[?(tags.[0] != float:1.5)]"},
+		{"body.users[?(@.email)]", "body.users.[?(email)]"},
+	}
+
+	for _, tt := range tests {
+		segments, err := ParsePath(tt.path)
+		if err != nil {
+			t.Errorf("ParsePath(%q): %v", tt.path, err)
+			continue
+		}
+		if got := formatSegments(segments); got != tt.want {
+			t.Errorf("ParsePath(%q) = %s, want %s", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestParsePathErrors(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"body.", "expected key"},
+		{"body.items[0", "missing ]"},
+		{"body.items[x]", "invalid index [x]"},
+		{"body.items[1:x]", "invalid index [1:x]"},
+		{`body["a`, "unterminated string"},
+		{`body["a"x]`, "missing ]"},
+		{"body.users[?(role == 1)]", "filter must start with @"},
+		{"body.users[?(@.role == 1]", "missing )"},
+		{"body.users[?(@.role ==)]", "expected value"},
+		{"body.users[?(@.a[?(@.b)])]", "nested filters are not supported"},
+		{"body]", `unexpected ']'`},
+	}
+
+	for _, tt := range tests {
+		_, err := ParsePath(tt.path)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParsePath(%q) err = %v, want %q", tt.path, err, tt.want)
+		}
+	}
+}
+
+// formatSegments writes segments compactly, one per dot: keys as is or
+// quoted when they hold a dot, indexes, slices and filters in brackets,
+// and a leading dot for recursive ones.
+func formatSegments(segments []model.PathSegment) string {
+	parts := make([]string, len(segments))
+	for i, seg := range segments {
+		var b strings.Builder
+		if seg.Recursive {
+			b.WriteByte('.')
+		}
+
+		switch {
+		case seg.Key != nil && strings.ContainsAny(*seg.Key, ".-"):
+			fmt.Fprintf(&b, "%q", *seg.Key)
+		case seg.Key != nil:
+			b.WriteString(*seg.Key)
+		case seg.Index != nil:
+			fmt.Fprintf(&b, "[%d]", *seg.Index)
+		case seg.Wildcard:
+			b.WriteString("[*]")
+		case seg.Slice != nil:
+			fmt.Fprintf(&b, "[%s:%s]", bound(seg.Slice.Start), bound(seg.Slice.End))
+		case seg.Filter != nil:
+			f := seg.Filter
+			if f.Op == "" {
+				fmt.Fprintf(&b, "[?(%s)]", formatSegments(f.Path))
+			} else {
+				fmt.Fprintf(&b, "[?(%s %s %s:%v)]", formatSegments(f.Path), f.Op, f.Value.Type, f.Value.Raw)
+			}
+		}
+		parts[i] = b.String()
+	}
+	return strings.Join(parts, ".")
+}
+
+func bound(n *int) string {
+	if n == nil {
+		return ""
+	}
+	return fmt.Sprint(*n)
+}
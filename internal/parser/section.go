@@ -1,42 +1,198 @@
 package parser
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/model"
+)
 
 func (p *parser) parseKeyValueSection(dst map[string]string) error {
+	return p.parseKeyValuePos(dst, nil)
+}
+
+// parseKeyValuePos is parseKeyValueSection that also records where each
+// value starts in positions, when not nil.
+func (p *parser) parseKeyValuePos(dst map[string]string, positions map[string]model.Pos) error {
 	for p.pos < len(p.lines) {
 		line := strings.TrimSpace(p.current().Text)
 
+		if isBlockEnd(line) {
+			return nil
+		}
+
 		if line == "" || strings.HasPrefix(line, "#") {
 			p.pos++
 			continue
 		}
 
-		if isSection(line) {
+		key, val, ok := strings.Cut(line, "=")
+		if !ok {
+			return p.error("expected key = value")
+		}
+
+		dst[strings.TrimSpace(key)] = strings.TrimSpace(val)
+		if positions != nil {
+			positions[strings.TrimSpace(key)] = p.valuePos()
+		}
+		p.pos++
+	}
+	return nil
+}
+
+// parseParamSection is parseKeyValueSection for sections where order
+// matters and keys may repeat.
+func (p *parser) parseParamSection(dst *[]model.Param) error {
+	for p.pos < len(p.lines) {
+		line := strings.TrimSpace(p.current().Text)
+
+		if isBlockEnd(line) {
 			return nil
 		}
 
+		if line == "" || strings.HasPrefix(line, "#") {
+			p.pos++
+			continue
+		}
+
 		key, val, ok := strings.Cut(line, "=")
 		if !ok {
 			return p.error("expected key = value")
 		}
 
-		dst[strings.TrimSpace(key)] = strings.TrimSpace(val)
+		*dst = append(*dst, model.Param{
+			Key:   strings.TrimSpace(key),
+			Value: strings.TrimSpace(val),
+			Pos:   p.valuePos(),
+		})
 		p.pos++
 	}
 	return nil
 }
 
-func (p *parser) parseBody() error {
+// parseText reads the raw lines up to the end of the section and
+// where they start.
+func (p *parser) parseText() (string, model.Pos) {
 	start := p.pos
 
-	for p.pos < len(p.lines) && !isSection(strings.TrimSpace(p.current().Text)) {
+	for p.pos < len(p.lines) && !isBlockEnd(strings.TrimSpace(p.current().Text)) {
 		p.pos++
 	}
 
-	p.doc.Body = collectLines(p.lines[start:p.pos])
+	var pos model.Pos
+	if start < len(p.lines) {
+		pos = model.Pos{Line: p.lines[start].Num, Col: 1}
+	}
+	return collectLines(p.lines[start:p.pos]), pos
+}
+
+// parseGraphQL reads the [graphql] query, or its variables when
+// variables is set.
+func (p *parser) parseGraphQL(variables bool) error {
+	if p.req.GraphQL == nil {
+		p.req.GraphQL = &model.GraphQL{}
+	}
+
+	text, pos := p.parseText()
+	if variables {
+		p.req.GraphQL.Variables, p.req.GraphQL.VariablesPos = text, pos
+	} else {
+		p.req.GraphQL.Query, p.req.GraphQL.QueryPos = text, pos
+	}
 	return nil
 }
 
+func (p *parser) parseBody() error {
+	start := p.pos
+	body, pos := p.parseText()
+
+	for i := start; i < p.pos; i++ {
+		if isBodyFile(strings.TrimSpace(p.lines[i].Text)) {
+			return p.parseBodyFile(i, start)
+		}
+	}
+
+	p.req.Body, p.req.BodyPos = body, pos
+	return nil
+}
+
+// parseMultipartSection reads field = value entries and file entries
+// like `file = @./report.pdf; type=application/pdf`.
+func (p *parser) parseMultipartSection() error {
+	var params []model.Param
+	if err := p.parseParamSection(&params); err != nil {
+		return err
+	}
+
+	for _, param := range params {
+		part, err := parsePart(param)
+		if err != nil {
+			return p.errorAt(param.Pos.Line, err.Error())
+		}
+		p.req.Multipart = append(p.req.Multipart, part)
+	}
+	return nil
+}
+
+func parsePart(param model.Param) (model.Part, error) {
+	part := model.Part{Key: param.Key, Value: param.Value, Pos: param.Pos}
+	if !strings.HasPrefix(param.Value, "@") {
+		return part, nil
+	}
+
+	fields := strings.Split(param.Value[1:], ";")
+	part.File = true
+	part.Value = strings.TrimSpace(fields[0])
+	part.Pos.Col++
+	if part.Value == "" {
+		return part, fmt.Errorf("expected file path after @")
+	}
+
+	for _, f := range fields[1:] {
+		key, val, ok := strings.Cut(strings.TrimSpace(f), "=")
+		if !ok || strings.TrimSpace(key) != "type" {
+			return part, fmt.Errorf("invalid file attribute: %s", strings.TrimSpace(f))
+		}
+		part.Type = strings.TrimSpace(val)
+	}
+	return part, nil
+}
+
+// parseBodyFile reads a `< path` or `<@ path` body at line i, which must
+// be the only content of the section.
+func (p *parser) parseBodyFile(i, start int) error {
+	line := p.lines[i]
+	for j := start; j < p.pos; j++ {
+		if j != i && strings.TrimSpace(p.lines[j].Text) != "" {
+			return p.errorAt(p.lines[j].Num, "body file can not be mixed with inline content")
+		}
+	}
+
+	text := strings.TrimSpace(line.Text)
+	template := strings.HasPrefix(text, "<@")
+	path := strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(text, "<"), "@"))
+
+	p.req.BodyFile = path
+	p.req.BodyTemplate = template
+	p.req.BodyPos = model.Pos{Line: line.Num, Col: strings.Index(line.Text, path) + 1}
+	return nil
+}
+
+// isBodyFile reports a `< path` or `<@ path` line. The space keeps XML
+// bodies like <user> inline.
+func isBodyFile(line string) bool {
+	return strings.HasPrefix(line, "< ") || strings.HasPrefix(line, "<\t") ||
+		strings.HasPrefix(line, "<@ ") || strings.HasPrefix(line, "<@\t")
+}
+
+// valuePos returns where the value of the current key = value line starts.
+func (p *parser) valuePos() model.Pos {
+	text := p.current().Text
+	_, val, _ := strings.Cut(text, "=")
+	start := len(text) - len(strings.TrimLeft(val, " \t"))
+	return model.Pos{Line: p.current().Num, Col: start + 1}
+}
+
 func isSection(line string) bool {
 	return strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]")
 }
@@ -1,30 +1,126 @@
 package resolver
 
-// TODO (MAHMOUD) - Default Values {{TOKEN|guest}}
-// TODO (MAHMOUD) - Nested resolution {{API_{{ENV}}}}
-// TODO (MAHMOUD) - Escaping \{{NOT_A_VAR}}
+import (
+	"os"
+	"strings"
+	"sync"
+)
 
+// envPrefix selects environment variables: {{env.HOME}}.
+const envPrefix = "env."
+
+// Context holds the variables templates resolve against. The .env
+// values and the process environment come last: they fill in names no
+// other source sets, and are always read for keys with the env. prefix.
 type Context struct {
+	mu     sync.RWMutex
 	values map[string]string
+	env    map[string]string
+
+	// literal marks values substituted as is, without expanding the
+	// templates they hold, e.g. captured responses and --var flags.
+	literal map[string]bool
+
+	// calls caches $function results for the lifetime of the context.
+	calls map[string]string
 }
 
 func NewContext() *Context {
 	return &Context{
 		values: make(map[string]string),
+		env:    make(map[string]string),
+		calls:  make(map[string]string),
+
+		literal: make(map[string]bool),
 	}
 }
 
 func (c *Context) Set(key string, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.values[key] = value
+	delete(c.literal, key)
 }
 
 func (c *Context) Get(key string) (string, bool) {
-	v, ok := c.values[key]
+	v, _, ok := c.lookup(key)
 	return v, ok
 }
 
+// lookup is Get that also reports a literal value. Environment values
+// are always literal.
+func (c *Context) lookup(key string) (string, bool, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if v, ok := c.values[key]; ok {
+		return v, c.literal[key], true
+	}
+
+	if name, ok := strings.CutPrefix(key, envPrefix); ok {
+		key = name
+	}
+	v, ok := c.lookupEnv(key)
+	return v, true, ok
+}
+
+// lookupEnv reads .env values first, then the process environment.
+func (c *Context) lookupEnv(key string) (string, bool) {
+	if v, ok := c.env[key]; ok {
+		return v, true
+	}
+	return os.LookupEnv(key)
+}
+
+// MergeEnv adds values loaded from .env files.
+func (c *Context) MergeEnv(env map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, v := range env {
+		c.env[k] = v
+	}
+}
+
+func (c *Context) Values() map[string]string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	values := make(map[string]string, len(c.values))
+	for k, v := range c.values {
+		values[k] = v
+	}
+	return values
+}
+
+// Merge adds values that may reference other variables.
 func (c *Context) Merge(resource map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, v := range resource {
+		c.values[k] = v
+		delete(c.literal, k)
+	}
+}
+
+// MergeLiteral adds values substituted as is, like captured responses
+// whose content may contain {{.
+func (c *Context) MergeLiteral(resource map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	for k, v := range resource {
 		c.values[k] = v
+		c.literal[k] = true
 	}
 }
+
+func (c *Context) cachedCall(expr string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	v, ok := c.calls[expr]
+	return v, ok
+}
+
+func (c *Context) storeCall(expr string, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls[expr] = value
+}
@@ -0,0 +1,173 @@
+package resolver
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/model"
+)
+
+// templateFunc is a dynamic value such as {{$uuid}} or {{$randomInt 1 100}}.
+type templateFunc func(args []string) (string, error)
+
+// filterFunc transforms a value in a pipe such as {{TOKEN | base64}}.
+type filterFunc func(value string) (string, error)
+
+var templateFuncs = map[string]templateFunc{
+	"uuid":         fnUUID,
+	"timestamp":    fnTimestamp,
+	"isoDate":      fnISODate,
+	"randomInt":    fnRandomInt,
+	"randomString": fnRandomString,
+	"randomEmail":  fnRandomEmail,
+}
+
+var templateFilters = map[string]filterFunc{
+	"base64":    filterBase64,
+	"sha256":    filterSHA256,
+	"urlencode": filterURLEncode,
+	"json":      filterJSON,
+}
+
+// call evaluates a $function. The result is cached on the context, so
+// the same call returns the same value everywhere in one request.
+func (in *interpolator) call(expr string, pos model.Pos) (string, error) {
+	fields := strings.Fields(strings.TrimPrefix(expr, "$"))
+	if len(fields) == 0 {
+		return "", &Error{Pos: pos, Msg: "missing function name"}
+	}
+
+	key := strings.Join(fields, " ")
+	if v, ok := in.ctx.cachedCall(key); ok {
+		return v, nil
+	}
+
+	fn, ok := templateFuncs[fields[0]]
+	if !ok {
+		return "", &Error{Pos: pos, Msg: "unknown function: $" + fields[0]}
+	}
+
+	v, err := fn(fields[1:])
+	if err != nil {
+		return "", &Error{Pos: pos, Msg: fmt.Sprintf("$%s: %v", fields[0], err)}
+	}
+
+	in.ctx.storeCall(key, v)
+	return v, nil
+}
+
+func fnUUID(args []string) (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+
+	h := hex.EncodeToString(b[:])
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:], nil
+}
+
+func fnTimestamp(args []string) (string, error) {
+	return strconv.FormatInt(time.Now().Unix(), 10), nil
+}
+
+func fnISODate(args []string) (string, error) {
+	return time.Now().UTC().Format(time.RFC3339), nil
+}
+
+// fnRandomInt returns an int in [min, max], 0 to 1000 by default.
+func fnRandomInt(args []string) (string, error) {
+	lo, hi := int64(0), int64(1000)
+
+	switch len(args) {
+	case 0:
+	case 2:
+		var err error
+		if lo, err = strconv.ParseInt(args[0], 10, 64); err != nil {
+			return "", fmt.Errorf("invalid min: %s", args[0])
+		}
+		if hi, err = strconv.ParseInt(args[1], 10, 64); err != nil {
+			return "", fmt.Errorf("invalid max: %s", args[1])
+		}
+	default:
+		return "", fmt.Errorf("expects min and max")
+	}
+
+	if hi < lo {
+		return "", fmt.Errorf("max %d is less than min %d", hi, lo)
+	}
+
+	n, err := rand.Int(rand.Reader, big.NewInt(hi-lo+1))
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(lo+n.Int64(), 10), nil
+}
+
+// fnRandomString returns lowercase letters and digits, 10 by default.
+func fnRandomString(args []string) (string, error) {
+	n := 10
+	if len(args) > 0 {
+		var err error
+		if n, err = strconv.Atoi(args[0]); err != nil || n < 1 {
+			return "", fmt.Errorf("invalid length: %s", args[0])
+		}
+	}
+	return randomString(n)
+}
+
+func fnRandomEmail(args []string) (string, error) {
+	name, err := randomString(10)
+	if err != nil {
+		return "", err
+	}
+	return "user_" + name + "@example.com", nil
+}
+
+func randomString(n int) (string, error) {
+	const alphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+	b := make([]byte, n)
+	for i := range b {
+		idx, err := rand.Int(rand.Reader, big.NewInt(int64(len(alphabet))))
+		if err != nil {
+			return "", err
+		}
+		b[i] = alphabet[idx.Int64()]
+	}
+	return string(b), nil
+}
+
+func filterBase64(value string) (string, error) {
+	return base64.StdEncoding.EncodeToString([]byte(value)), nil
+}
+
+func filterSHA256(value string) (string, error) {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func filterURLEncode(value string) (string, error) {
+	return url.QueryEscape(value), nil
+}
+
+// filterJSON encodes the value as a JSON string, quotes included.
+func filterJSON(value string) (string, error) {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(value); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
@@ -3,34 +3,274 @@ package resolver
 import (
 	"fmt"
 	"strings"
+
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/model"
 )
 
-func interpolate(raw string, ctx *Context) (string, error) {
+// maxDepth bounds nested templates and variables that reference other
+// variables, so self-referencing values fail instead of looping.
+const maxDepth = 10
+
+// Error is a template error at a position in the document.
+type Error struct {
+	Pos model.Pos
+	Msg string
+}
+
+func (e *Error) Error() string {
+	if e.Pos.Line == 0 {
+		return e.Msg
+	}
+	return fmt.Sprintf("line %d, col %d: %s", e.Pos.Line, e.Pos.Col, e.Msg)
+}
+
+type interpolator struct {
+	ctx *Context
+	raw string
+	pos model.Pos
+}
+
+// interpolate replaces every {{...}} in raw. pos is where raw starts in
+// the document and is used to locate errors.
+//
+//	{{NAME}}           value of NAME
+//	{{NAME|default}}   default when NAME is undefined
+//	{{NAME | base64}}  NAME passed through a filter
+//	{{$uuid}}          dynamic value, see templateFuncs
+//	{{API_{{ENV}}}}    inner templates resolve first
+//	\{{NAME}}          literal {{NAME}}
+func interpolate(raw string, ctx *Context, pos model.Pos) (string, error) {
+	in := &interpolator{ctx: ctx, raw: raw, pos: pos}
+	return in.expand(raw, in.posAt, 0)
+}
+
+// posAt maps an offset in raw to a document position.
+func (in *interpolator) posAt(offset int) model.Pos {
+	if in.pos.Line == 0 {
+		return model.Pos{}
+	}
+
+	before := in.raw[:offset]
+	nl := strings.LastIndex(before, "\n")
+	if nl == -1 {
+		return model.Pos{Line: in.pos.Line, Col: in.pos.Col + offset}
+	}
+	return model.Pos{
+		Line: in.pos.Line + strings.Count(before, "\n"),
+		Col:  offset - nl,
+	}
+}
+
+// expand resolves the templates of s. at maps offsets in s to positions.
+func (in *interpolator) expand(s string, at func(int) model.Pos, depth int) (string, error) {
 	var out strings.Builder
 
-	for {
-		start := strings.Index(raw, "{{")
-		if start == -1 {
-			out.WriteString(raw)
-			break
+	for i := 0; i < len(s); {
+		if strings.HasPrefix(s[i:], `\{{`) {
+			out.WriteString("{{")
+			i += 3
+			continue
 		}
 
-		end := strings.Index(raw[start+2:], "}}")
+		if !strings.HasPrefix(s[i:], "{{") {
+			out.WriteByte(s[i])
+			i++
+			continue
+		}
+
+		if depth >= maxDepth {
+			return "", &Error{Pos: at(i), Msg: "template nesting too deep"}
+		}
+
+		end := matchTemplate(s, i)
 		if end == -1 {
-			return "", fmt.Errorf("unterminated template")
+			return "", &Error{Pos: at(i), Msg: "unterminated template"}
 		}
 
-		out.WriteString(raw[:start])
+		start := i + 2
+		expr, err := in.expand(s[start:end], func(j int) model.Pos { return at(start + j) }, depth+1)
+		if err != nil {
+			return "", err
+		}
 
-		key := raw[start+2 : start+2+end]
-		val, ok := ctx.Get(key)
-		if !ok {
-			return "", fmt.Errorf("undefined variable: %s", key)
+		val, err := in.eval(strings.TrimSpace(expr), at(i), depth)
+		if err != nil {
+			return "", err
 		}
 
 		out.WriteString(val)
-		raw = raw[start+2+end+2:]
+		i = end + 2
 	}
 
 	return out.String(), nil
 }
+
+// eval resolves a single template expression: a variable or $function,
+// an optional default right after it, then filters. A quoted default may
+// contain | or be a filter name: {{FORMAT|"json"}}. A bare stage naming
+// a filter is the filter; any other bare stage after the first is an
+// unknown filter, as is a first one that misspells a filter.
+func (in *interpolator) eval(expr string, pos model.Pos, depth int) (string, error) {
+	stages := splitStages(expr)
+	source := strings.TrimSpace(stages[0])
+
+	// check every stage first, so a misplaced default is reported
+	// rather than an undefined variable
+	var filters []string
+	def, hasDefault := "", false
+	for i, stage := range stages[1:] {
+		stage = strings.TrimSpace(stage)
+		if _, ok := templateFilters[stage]; ok {
+			filters = append(filters, stage)
+			continue
+		}
+
+		d, err := parseDefault(stage, i == 0)
+		if err != nil {
+			return "", &Error{Pos: pos, Msg: err.Error()}
+		}
+		def, hasDefault = d, true
+	}
+
+	val, ok, err := in.lookup(source, pos, depth)
+	if err != nil {
+		return "", err
+	}
+	if !ok && !hasDefault {
+		return "", &Error{Pos: pos, Msg: "undefined variable: " + source}
+	}
+	if !ok {
+		val = def
+	}
+
+	for _, name := range filters {
+		val, err = templateFilters[name](val)
+		if err != nil {
+			return "", &Error{Pos: pos, Msg: fmt.Sprintf("filter %s: %v", name, err)}
+		}
+	}
+	return val, nil
+}
+
+// parseDefault reads the default of a stage that is not a filter. first
+// is set for the stage right after the source, the only place a default
+// may go, so {{NAME|base64|guest}} fails rather than encode nothing.
+func parseDefault(stage string, first bool) (string, error) {
+	quoted := len(stage) >= 2 && strings.HasPrefix(stage, `"`) && strings.HasSuffix(stage, `"`)
+
+	switch {
+	case !first && quoted:
+		return "", fmt.Errorf("default %s must come right after the variable", stage)
+	case !first:
+		return "", fmt.Errorf("unknown filter: %s", stage)
+	case quoted:
+		return stage[1 : len(stage)-1], nil
+	}
+
+	for name := range templateFilters {
+		if misspells(stage, name) {
+			return "", fmt.Errorf("unknown filter: %s, quote the default if it is one", stage)
+		}
+	}
+	return stage, nil
+}
+
+// misspells reports a stage within two edits of a filter name and of
+// about its length, like bas64 or sha265.
+func misspells(stage, name string) bool {
+	if len(stage) < len(name)-1 || len(stage) > len(name)+1 {
+		return false
+	}
+	return distance(stage, name) <= 2
+}
+
+// distance is the Levenshtein distance between a and b.
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// splitStages splits expr on the pipes outside double quotes.
+func splitStages(expr string) []string {
+	var stages []string
+	inQuotes := false
+	start := 0
+
+	for i := 0; i < len(expr); i++ {
+		switch expr[i] {
+		case '"':
+			inQuotes = !inQuotes
+		case '|':
+			if !inQuotes {
+				stages = append(stages, expr[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(stages, expr[start:])
+}
+
+// lookup returns the value of a variable or $function. Values from
+// [context] and [vars] may hold templates and are expanded in turn.
+// Captured, --var and environment values are substituted as is.
+func (in *interpolator) lookup(source string, pos model.Pos, depth int) (string, bool, error) {
+	if strings.HasPrefix(source, "$") {
+		val, err := in.call(source, pos)
+		return val, err == nil, err
+	}
+
+	val, literal, ok := in.ctx.lookup(source)
+	if !ok {
+		return "", false, nil
+	}
+	if literal {
+		return val, true, nil
+	}
+
+	if depth+1 >= maxDepth {
+		return "", false, &Error{Pos: pos, Msg: "recursion limit exceeded resolving " + source}
+	}
+
+	val, err := in.expand(val, func(int) model.Pos { return pos }, depth+1)
+	return val, err == nil, err
+}
+
+// matchTemplate returns the index of the }} closing the template that
+// opens at start, skipping nested and escaped templates.
+func matchTemplate(s string, start int) int {
+	depth := 0
+	for i := start + 2; i < len(s); {
+		switch {
+		case strings.HasPrefix(s[i:], `\{{`):
+			i += 3
+		case strings.HasPrefix(s[i:], "{{"):
+			depth++
+			i += 2
+		case strings.HasPrefix(s[i:], "}}"):
+			if depth == 0 {
+				return i
+			}
+			depth--
+			i += 2
+		default:
+			i++
+		}
+	}
+	return -1
+}
@@ -0,0 +1,206 @@
+package resolver
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/model"
+)
+
+func newTestContext() *Context {
+	ctx := NewContext()
+	ctx.Merge(map[string]string{
+		"HOST":     "example.com",
+		"BASE":     "https://{{HOST}}/v1",
+		"URL":      "{{BASE}}/users",
+		"SELF":     "{{SELF}}",
+		"PING":     "{{PONG}}",
+		"PONG":     "{{PING}}",
+		"TOKEN":    "abc",
+		"ENV":      "PROD",
+		"API_PROD": "https://api.example.com",
+		"EMPTY":    "",
+	})
+	ctx.MergeLiteral(map[string]string{
+		"CAPTURED": "{{HOST}}",
+	})
+	ctx.MergeEnv(map[string]string{
+		"DOTENV": "from-dotenv",
+		"TOKEN":  "from-dotenv",
+	})
+	return ctx
+}
+
+func TestInterpolate(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"plain text", "plain text"},
+		{"{{TOKEN}}", "abc"},
+		{"{{ TOKEN }}", "abc"},
+		{"Bearer {{TOKEN}}!", "Bearer abc!"},
+		{"{{EMPTY}}", ""},
+
+		// nested resolution
+		{"{{BASE}}", "https://example.com/v1"},
+		{"{{URL}}", "https://example.com/v1/users"},
+		{"{{API_{{ENV}}}}", "https://api.example.com"},
+
+		// captured and --var values are literal
+		{"{{CAPTURED}}", "{{HOST}}"},
+
+		// environment, below every other source
+		{"{{DOTENV}}", "from-dotenv"},
+		{"{{env.DOTENV}}", "from-dotenv"},
+		{"{{env.TOKEN}}", "from-dotenv"},
+
+		// escaping
+		{`\{{TOKEN}}`, "{{TOKEN}}"},
+		{`{{TOKEN}} \{{TOKEN}}`, "abc {{TOKEN}}"},
+
+		// defaults
+		{"{{MISSING|guest}}", "guest"},
+		{"{{MISSING | guest}}", "guest"},
+		{"{{TOKEN|guest}}", "abc"},
+		{`{{MISSING|"json"}}`, "json"},
+		{`{{MISSING|"a|b"}}`, "a|b"},
+		{`{{MISSING|""}}`, ""},
+		{"{{MISSING|guest|base64}}", "Z3Vlc3Q="},
+
+		// filters
+		{"{{TOKEN | base64}}", "YWJj"},
+		{"{{TOKEN|sha256}}", "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{"{{MISSING|a b&c|urlencode}}", "a+b%26c"},
+		{`{{MISSING|"say \"hi\""|json}}`, `"say \\\"hi\\\""`},
+		{"{{TOKEN|base64|base64}}", "WVdKag=="},
+	}
+
+	for _, tt := range tests {
+		got, err := interpolate(tt.raw, newTestContext(), model.Pos{})
+		if err != nil {
+			t.Errorf("interpolate(%q): %v", tt.raw, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("interpolate(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestInterpolateErrors(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"{{MISSING}}", "line 3, col 5: undefined variable: MISSING"},
+		{"{{HOME_OF_NOBODY}}", "line 3, col 5: undefined variable: HOME_OF_NOBODY"},
+		{"ab\n  {{MISSING}}", "line 4, col 3: undefined variable: MISSING"},
+		{"{{MISSING|base64}}", "undefined variable: MISSING"},
+		{"{{SELF}}", "line 3, col 5: recursion limit exceeded resolving SELF"},
+		{"{{PING}}", "recursion limit exceeded resolving P"},
+		{"{{TOKEN | bas64}}", "unknown filter: bas64"},
+		{"{{TOKEN|sha265}}", "unknown filter: sha265"},
+		{"{{MISSING|base64|guest}}", "unknown filter: guest"},
+		{`{{MISSING|base64|"guest"}}`, `default "guest" must come right after the variable`},
+		{"{{TOKEN", "unterminated template"},
+		{"{{$nope}}", "unknown function: $nope"},
+		{"{{$}}", "missing function name"},
+		{"{{$randomInt 5}}", "$randomInt: expects min and max"},
+		{"{{$randomInt 9 1}}", "$randomInt: max 1 is less than min 9"},
+		{"{{$randomString x}}", "$randomString: invalid length: x"},
+	}
+
+	for _, tt := range tests {
+		_, err := interpolate(tt.raw, newTestContext(), model.Pos{Line: 3, Col: 5})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("interpolate(%q) err = %v, want %q", tt.raw, err, tt.want)
+		}
+	}
+}
+
+func TestInterpolateFunctions(t *testing.T) {
+	tests := []struct {
+		raw     string
+		pattern string
+	}{
+		{"{{$uuid}}", `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`},
+		{"{{$timestamp}}", `^[0-9]{10,}$`},
+		{"{{$isoDate}}", `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z$`},
+		{"{{$randomString 8}}", `^[a-z0-9]{8}$`},
+		{"{{$randomEmail}}", `^user_[a-z0-9]{10}@example\.com$`},
+		{"{{$uuid | base64}}", `^[A-Za-z0-9+/]+=*$`},
+	}
+
+	for _, tt := range tests {
+		got, err := interpolate(tt.raw, newTestContext(), model.Pos{})
+		if err != nil {
+			t.Errorf("interpolate(%q): %v", tt.raw, err)
+			continue
+		}
+		if !regexp.MustCompile(tt.pattern).MatchString(got) {
+			t.Errorf("interpolate(%q) = %q, want match for %s", tt.raw, got, tt.pattern)
+		}
+	}
+}
+
+func TestInterpolateRandomInt(t *testing.T) {
+	for range 50 {
+		got, err := interpolate("{{$randomInt 1 3}}", newTestContext(), model.Pos{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		n, err := strconv.Atoi(got)
+		if err != nil || n < 1 || n > 3 {
+			t.Fatalf("$randomInt 1 3 = %q", got)
+		}
+	}
+}
+
+func TestInterpolateCachesCalls(t *testing.T) {
+	ctx := newTestContext()
+
+	got, err := interpolate("{{$uuid}} {{$uuid}}", ctx, model.Pos{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, second, _ := strings.Cut(got, " ")
+	if first != second {
+		t.Errorf("$uuid differs within a context: %q", got)
+	}
+
+	other, err := interpolate("{{$uuid}}", newTestContext(), model.Pos{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == first {
+		t.Errorf("$uuid repeated across contexts: %q", other)
+	}
+}
+
+func TestContextPrecedence(t *testing.T) {
+	ctx := NewContext()
+	ctx.MergeEnv(map[string]string{"NAME": "dotenv"})
+	if got, _ := ctx.Get("NAME"); got != "dotenv" {
+		t.Errorf("NAME = %q, want the .env value", got)
+	}
+
+	ctx.Merge(map[string]string{"NAME": "config"})
+	if got, _ := ctx.Get("NAME"); got != "config" {
+		t.Errorf("NAME = %q, want the config value over .env", got)
+	}
+	if got, _ := ctx.Get("env.NAME"); got != "dotenv" {
+		t.Errorf("env.NAME = %q, want the .env value", got)
+	}
+
+	t.Setenv("ZYRA_TEST_NAME", "os")
+	if got, _ := ctx.Get("ZYRA_TEST_NAME"); got != "os" {
+		t.Errorf("ZYRA_TEST_NAME = %q, want the OS value", got)
+	}
+	ctx.MergeEnv(map[string]string{"ZYRA_TEST_NAME": "dotenv"})
+	if got, _ := ctx.Get("ZYRA_TEST_NAME"); got != "dotenv" {
+		t.Errorf("ZYRA_TEST_NAME = %q, want .env over the OS value", got)
+	}
+}
@@ -2,47 +2,87 @@ package resolver
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/Mahmoud-Khaled-FS/zyra/internal/model"
 )
 
-func ResolveDocument(doc *model.Document, ctx *Context) (*model.Document, error) {
-	cp := doc.Clone()
+// ResolveRequest interpolates a copy of req, one of the requests in doc.
+func ResolveRequest(doc *model.Document, req *model.Request, ctx *Context) (*model.Request, error) {
+	cp := req.Clone()
 
 	var err error
 
-	cp.Path, err = interpolate(doc.Path, ctx)
+	cp.Path, err = interpolate(req.Path, ctx, req.PathPos)
 	if err != nil {
 		return nil, err
 	}
 
-	for k, v := range doc.Headers {
-		cp.Headers[k], err = interpolate(v, ctx)
+	for i, h := range req.Headers {
+		cp.Headers[i].Value, err = interpolate(h.Value, ctx, h.Pos)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	for k, v := range doc.Query {
-		cp.Query[k], err = interpolate(v, ctx)
+	for i, q := range req.Query {
+		cp.Query[i].Value, err = interpolate(q.Value, ctx, q.Pos)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	cp.Body, err = interpolate(doc.Body, ctx)
+	for i, f := range req.Form {
+		cp.Form[i].Value, err = interpolate(f.Value, ctx, f.Pos)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i, part := range req.Multipart {
+		cp.Multipart[i].Value, err = interpolate(part.Value, ctx, part.Pos)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if g := req.GraphQL; g != nil {
+		cp.GraphQL.Query, err = interpolate(g.Query, ctx, g.QueryPos)
+		if err != nil {
+			return nil, err
+		}
+
+		cp.GraphQL.Variables, err = interpolate(g.Variables, ctx, g.VariablesPos)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for k, v := range req.Options {
+		cp.Options[k], err = interpolate(v, ctx, req.OptionsPos[k])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cp.Body, err = interpolate(req.Body, ctx, req.BodyPos)
+	if err != nil {
+		return nil, err
+	}
+
+	cp.BodyFile, err = interpolate(req.BodyFile, ctx, req.BodyPos)
 	if err != nil {
 		return nil, err
 	}
 
 	for _, a := range cp.Assertions {
 		for i, arg := range a.Args {
-			if arg.Type == "template" {
+			if arg.Type == "template" || isTemplateString(arg) {
 				v, ok := arg.Raw.(string)
 				if !ok {
 					return nil, fmt.Errorf("Can not parse %v", arg.Raw)
 				}
-				raw, err := interpolate(v, ctx)
+				raw, err := interpolate(v, ctx, argPos(doc, a.Line, v))
 				if err != nil {
 					return nil, err
 				}
@@ -53,3 +93,32 @@ func ResolveDocument(doc *model.Document, ctx *Context) (*model.Document, error)
 
 	return cp, nil
 }
+
+// ResolveFile interpolates the content of a body file, errors are
+// located in that file.
+func ResolveFile(name, content string, ctx *Context) (string, error) {
+	out, err := interpolate(content, ctx, model.Pos{Line: 1, Col: 1})
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	return out, nil
+}
+
+// argPos locates an assertion argument in its source line.
+func argPos(doc *model.Document, line int, arg string) model.Pos {
+	if line < 1 || line > len(doc.Lines) {
+		return model.Pos{}
+	}
+
+	col := strings.Index(doc.Lines[line-1].Text, arg)
+	if col == -1 {
+		return model.Pos{}
+	}
+	return model.Pos{Line: line, Col: col + 1}
+}
+
+// isTemplateString reports a quoted argument holding templates, e.g. "id-{{ID}}".
+func isTemplateString(arg model.Value) bool {
+	s, ok := arg.Raw.(string)
+	return ok && arg.Type == "string" && strings.Contains(s, "{{")
+}
@@ -0,0 +1,107 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+)
+
+// maxValue caps the length of values quoted in a diff.
+const maxValue = 40
+
+// Diff lists the differences between the expected and actual JSON
+// values, each prefixed with its path under root, e.g.
+//
+//	body.user.name: expected "Ada", got "Bob"
+//	body.user.age: unexpected 36
+//	body.tags[2]: missing, expected "admin"
+func Diff(root string, expected, actual any) []string {
+	var out []string
+	diff(root, expected, actual, &out)
+	return out
+}
+
+func diff(path string, expected, actual any, out *[]string) {
+	switch e := expected.(type) {
+	case map[string]any:
+		a, ok := actual.(map[string]any)
+		if !ok {
+			break
+		}
+
+		for _, k := range unionKeys(e, a) {
+			ev, inExpected := e[k]
+			av, inActual := a[k]
+			switch {
+			case !inActual:
+				*out = append(*out, fmt.Sprintf("%s: missing, expected %s", keyPath(path, k), format(ev)))
+			case !inExpected:
+				*out = append(*out, fmt.Sprintf("%s: unexpected %s", keyPath(path, k), format(av)))
+			default:
+				diff(keyPath(path, k), ev, av, out)
+			}
+		}
+		return
+
+	case []any:
+		a, ok := actual.([]any)
+		if !ok {
+			break
+		}
+
+		for i := 0; i < max(len(e), len(a)); i++ {
+			p := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(a):
+				*out = append(*out, fmt.Sprintf("%s: missing, expected %s", p, format(e[i])))
+			case i >= len(e):
+				*out = append(*out, fmt.Sprintf("%s: unexpected %s", p, format(a[i])))
+			default:
+				diff(p, e[i], a[i], out)
+			}
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		*out = append(*out, fmt.Sprintf("%s: expected %s, got %s", path, format(expected), format(actual)))
+	}
+}
+
+func unionKeys(a, b map[string]any) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+func keyPath(path, key string) string {
+	if identifier.MatchString(key) {
+		return path + "." + key
+	}
+	return fmt.Sprintf("%s[%q]", path, key)
+}
+
+func format(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	s := string(data)
+	if len(s) > maxValue {
+		s = s[:maxValue-3] + "..."
+	}
+	return s
+}
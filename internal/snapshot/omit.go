@@ -0,0 +1,103 @@
+package snapshot
+
+import (
+	"strings"
+
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/model"
+)
+
+// Omit removes the values selected by path from v, a normalized JSON
+// value, and returns the result. Keys, indexes, [*] and ..key are
+// supported; keys fall back to a case-insensitive match so header
+// names need not be canonical.
+func Omit(v any, path []model.PathSegment) any {
+	if len(path) == 0 {
+		return v
+	}
+
+	seg, rest := path[0], path[1:]
+	if seg.Recursive {
+		return omitRecursive(v, seg, rest)
+	}
+
+	switch t := v.(type) {
+	case map[string]any:
+		switch {
+		case seg.Key != nil:
+			k, ok := findKey(t, *seg.Key)
+			if !ok {
+				break
+			}
+			if len(rest) == 0 {
+				delete(t, k)
+			} else {
+				t[k] = Omit(t[k], rest)
+			}
+		case seg.Wildcard:
+			for k, item := range t {
+				if len(rest) == 0 {
+					delete(t, k)
+				} else {
+					t[k] = Omit(item, rest)
+				}
+			}
+		}
+		return t
+
+	case []any:
+		switch {
+		case seg.Index != nil:
+			i := *seg.Index
+			if i < 0 {
+				i += len(t)
+			}
+			if i < 0 || i >= len(t) {
+				break
+			}
+			if len(rest) == 0 {
+				return append(t[:i:i], t[i+1:]...)
+			}
+			t[i] = Omit(t[i], rest)
+		case seg.Wildcard:
+			if len(rest) == 0 {
+				return []any{}
+			}
+			for i, item := range t {
+				t[i] = Omit(item, rest)
+			}
+		}
+		return t
+	}
+	return v
+}
+
+// omitRecursive applies ..key at every depth of v.
+func omitRecursive(v any, seg model.PathSegment, rest []model.PathSegment) any {
+	here := seg
+	here.Recursive = false
+	v = Omit(v, append([]model.PathSegment{here}, rest...))
+
+	switch t := v.(type) {
+	case map[string]any:
+		for k, item := range t {
+			t[k] = omitRecursive(item, seg, rest)
+		}
+	case []any:
+		for i, item := range t {
+			t[i] = omitRecursive(item, seg, rest)
+		}
+	}
+	return v
+}
+
+func findKey(m map[string]any, key string) (string, bool) {
+	if _, ok := m[key]; ok {
+		return key, true
+	}
+	for k := range m {
+		if strings.EqualFold(k, key) {
+			return k, true
+		}
+	}
+	return "", false
+}
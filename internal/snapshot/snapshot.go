@@ -0,0 +1,185 @@
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Dir is the directory, next to the .zyra files, holding their snapshots.
+const Dir = ".snapshots"
+
+// maxDiffs caps the differences listed in a mismatch.
+const maxDiffs = 10
+
+// Store reads and writes the snapshot files of a run. Each .zyra file
+// has one JSON file mapping snapshot keys to the recorded values.
+type Store struct {
+	// Update overwrites mismatching snapshots instead of failing.
+	Update bool
+
+	mu    sync.Mutex
+	files map[string]map[string]any
+
+	// used holds the keys matched in the run, by file, and done the
+	// files whose requests all ran; Prune drops their unused keys.
+	used map[string]map[string]bool
+	done map[string]bool
+}
+
+func NewStore(update bool) *Store {
+	return &Store{
+		Update: update,
+		files:  make(map[string]map[string]any),
+		used:   make(map[string]map[string]bool),
+		done:   make(map[string]bool),
+	}
+}
+
+// File returns the snapshot file of a .zyra file.
+func File(zyraFile string) string {
+	name := strings.TrimSuffix(filepath.Base(zyraFile), filepath.Ext(zyraFile))
+	return filepath.Join(filepath.Dir(zyraFile), Dir, name+".json")
+}
+
+// Match compares actual with the snapshot key of file, root names
+// actual in differences. A missing snapshot is recorded and passes, as
+// does a mismatch in update mode.
+func (s *Store) Match(file, key, root string, actual any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.load(file)
+	if err != nil {
+		return err
+	}
+
+	if s.used[file] == nil {
+		s.used[file] = make(map[string]bool)
+	}
+	s.used[file][key] = true
+
+	expected, ok := entries[key]
+	if ok && reflect.DeepEqual(expected, actual) {
+		return nil
+	}
+
+	if ok && !s.Update {
+		diffs := Diff(root, expected, actual)
+		if len(diffs) > maxDiffs {
+			diffs = append(diffs[:maxDiffs], fmt.Sprintf("and %d more", len(diffs)-maxDiffs))
+		}
+		return fmt.Errorf("snapshot %q differs: %s", key, strings.Join(diffs, "; "))
+	}
+
+	entries[key] = actual
+	return save(file, entries)
+}
+
+// Done marks a snapshot file whose requests all ran, so every key it
+// still needs was matched.
+func (s *Store) Done(file string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.done[file] = true
+}
+
+// Prune removes, in update mode, the keys of done files that no
+// assertion matched, and the files left empty.
+func (s *Store) Prune() error {
+	if !s.Update {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for file := range s.done {
+		entries, err := s.load(file)
+		if err != nil {
+			return err
+		}
+
+		pruned := false
+		for key := range entries {
+			if !s.used[file][key] {
+				delete(entries, key)
+				pruned = true
+			}
+		}
+		if !pruned {
+			continue
+		}
+
+		if len(entries) == 0 {
+			if err := os.Remove(file); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := save(file, entries); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) load(file string) (map[string]any, error) {
+	if entries, ok := s.files[file]; ok {
+		return entries, nil
+	}
+
+	entries := make(map[string]any)
+	data, err := os.ReadFile(file)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return nil, err
+	default:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		if err := decoder.Decode(&entries); err != nil {
+			return nil, fmt.Errorf("read snapshot %s: %w", file, err)
+		}
+	}
+
+	s.files[file] = entries
+	return entries, nil
+}
+
+func save(file string, entries map[string]any) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(entries); err != nil {
+		return err
+	}
+	return os.WriteFile(file, buf.Bytes(), 0o644)
+}
+
+// Normalize turns v into the plain JSON values a snapshot file holds,
+// so a response compares equal to its recorded snapshot.
+func Normalize(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var out any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,91 @@
+package zyra
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/model"
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/resolver"
+)
+
+// requestBody is the payload of a request: the inline [body], a file
+// streamed as is, or a file template read once and interpolated.
+type requestBody struct {
+	reader io.Reader
+	size   int64
+	file   *os.File
+}
+
+// openBody prepares the body of req. File paths are relative to dir,
+// the directory of the .zyra file. Close releases an opened file.
+func openBody(req *model.Request, dir string, ctx *resolver.Context) (*requestBody, error) {
+	if req.BodyFile == "" {
+		return &requestBody{
+			reader: strings.NewReader(req.Body),
+			size:   int64(len(req.Body)),
+		}, nil
+	}
+
+	path := req.BodyFile
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+
+	if req.BodyTemplate {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := resolver.ResolveFile(req.BodyFile, string(data), ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &requestBody{
+			reader: strings.NewReader(body),
+			size:   int64(len(body)),
+		}, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	stat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &requestBody{reader: f, size: stat.Size(), file: f}, nil
+}
+
+// resolveParts makes the file paths of parts relative to dir and checks
+// they can be read before the upload starts.
+func resolveParts(parts []model.Part, dir string) error {
+	for i, part := range parts {
+		if !part.File {
+			continue
+		}
+
+		path := part.Value
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		if _, err := os.Stat(path); err != nil {
+			return err
+		}
+		parts[i].Value = path
+	}
+	return nil
+}
+
+func (b *requestBody) Close() error {
+	if b.file == nil {
+		return nil
+	}
+	return b.file.Close()
+}
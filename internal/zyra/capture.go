@@ -0,0 +1,51 @@
+package zyra
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/assert"
+	httpclient "github.com/Mahmoud-Khaled-FS/zyra/internal/httpClient"
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/model"
+)
+
+// capture stores the values listed in the request [capture] section
+// so later requests in the same run can interpolate them.
+func (z *Zyra) capture(resp *httpclient.ZyraResponse, req *model.Request) []error {
+	var errs []error
+
+	for _, c := range req.Captures {
+		value, err := assert.ResolvePath(resp, c.Path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: capture %s: %w", c.Line, c.Name, err))
+			continue
+		}
+
+		z.Captures.Set(c.Name, captureString(value))
+	}
+
+	return errs
+}
+
+func captureString(value any) string {
+	if m, ok := value.(assert.Matches); ok {
+		value = m.Values()
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case nil:
+		return "null"
+	case map[string]any, []any:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(b)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,81 @@
+package zyra
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/assert/builtin"
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/logger"
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/openapi"
+)
+
+type CoverageOptions struct {
+	Path       string
+	ConfigPath string
+	Env        string
+}
+
+// Coverage runs the files of a directory and prints the operations and
+// status codes of the openapi spec that no response exercised.
+func Coverage(options CoverageOptions) error {
+	builtin.InitBuiltin()
+
+	zDir, err := loadDir(options.Path)
+	if err != nil {
+		return configFailure(err)
+	}
+
+	if options.ConfigPath != "" {
+		zDir.configPath = options.ConfigPath
+	}
+
+	config, err := loadConfig(zDir.configPath, options.Env)
+	if err != nil {
+		return configFailure(err)
+	}
+
+	z, err := newRunZyra(RunOption{Env: options.Env}, config, options.Path)
+	if err != nil {
+		return configFailure(err)
+	}
+	defer z.Transports.CloseIdleConnections()
+	if z.Spec == nil {
+		return configFailure(fmt.Errorf("coverage requires the openapi option in %s", configFileName))
+	}
+
+	if _, err := runDirSync(zDir, z); err != nil {
+		return configFailure(err)
+	}
+
+	printCoverage(z.Spec.Coverage())
+	return nil
+}
+
+func printCoverage(ops []openapi.OperationCoverage) {
+	width := 0
+	for _, c := range ops {
+		width = max(width, len(c.Operation.Path))
+	}
+
+	coveredOps, responses, coveredResponses := 0, 0, 0
+	for _, c := range ops {
+		if len(c.Covered) > 0 {
+			coveredOps++
+		}
+		responses += len(c.Covered) + len(c.Missing)
+		coveredResponses += len(c.Covered)
+
+		var statuses []string
+		for _, s := range c.Operation.Statuses {
+			if slices.Contains(c.Covered, s) {
+				statuses = append(statuses, "✔ "+s)
+			} else {
+				statuses = append(statuses, "✖ "+s)
+			}
+		}
+		fmt.Printf("%s  %-*s  %s\n", logger.MethodColor(c.Operation.Method), width, c.Operation.Path, strings.Join(statuses, "  "))
+	}
+
+	fmt.Printf("\nOperations: %d/%d covered  Responses: %d/%d covered\n", coveredOps, len(ops), coveredResponses, responses)
+}
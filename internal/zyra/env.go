@@ -0,0 +1,44 @@
+package zyra
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+type ListEnvsOptions struct {
+	Path       string
+	ConfigPath string
+}
+
+// ListEnvs prints the profiles declared in the config of a project.
+func ListEnvs(options ListEnvsOptions) error {
+	path := options.ConfigPath
+	if path == "" {
+		path = filepath.Join(options.Path, configFileName)
+	}
+
+	config, err := loadConfig(path, "")
+	if err != nil {
+		return err
+	}
+
+	names := config.ProfileNames()
+	if len(names) == 0 {
+		fmt.Println("No envs defined")
+		return nil
+	}
+
+	width := 0
+	for _, name := range names {
+		width = max(width, len(name))
+	}
+
+	for _, name := range names {
+		baseURL := config.Options["base_url"]
+		if v, ok := config.Profiles[name].Options["base_url"]; ok {
+			baseURL = v
+		}
+		fmt.Printf("%-*s  %s\n", width, name, baseURL)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package zyra
+
+import (
+	"errors"
+	"fmt"
+
+	httpclient "github.com/Mahmoud-Khaled-FS/zyra/internal/httpClient"
+)
+
+// Process exit codes of zyra run.
+const (
+	ExitOK        = 0
+	ExitAssertion = 1
+	ExitConfig    = 2
+	ExitTransport = 3
+)
+
+// ExitError ends the process with Code. Err, when set, is printed.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit status %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+func configFailure(err error) error {
+	return &ExitError{Code: ExitConfig, Err: err}
+}
+
+// configError marks a file that could not be turned into a request,
+// e.g. an undefined variable or an invalid option.
+type configError struct {
+	err error
+}
+
+func (e *configError) Error() string {
+	return e.err.Error()
+}
+
+func (e *configError) Unwrap() error {
+	return e.err
+}
+
+// exitCode picks the most severe failure of the run: transport errors,
+// then config errors, then failed assertions.
+func exitCode(results []ZyraResult) int {
+	code := ExitOK
+	for _, r := range results {
+		for _, err := range r.Errors {
+			code = max(code, errorExitCode(err))
+		}
+	}
+	return code
+}
+
+func errorExitCode(err error) int {
+	var transportErr *httpclient.TransportError
+	if errors.As(err, &transportErr) {
+		return ExitTransport
+	}
+
+	var cfgErr *configError
+	if errors.As(err, &cfgErr) {
+		return ExitConfig
+	}
+
+	return ExitAssertion
+}
+
+func runFailure(results []ZyraResult) error {
+	code := exitCode(results)
+	if code == ExitOK {
+		return nil
+	}
+	return &ExitError{Code: code}
+}
@@ -0,0 +1,199 @@
+package zyra
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// runPlan holds the files of a directory in the order they must run.
+// deps[i] lists the indices (into files) that files[i] depends on.
+type runPlan struct {
+	files []ZyraFile
+	deps  [][]int
+}
+
+// planDir builds the dependency graph declared through [meta] depends
+// and sorts it topologically. Files without dependencies keep the
+// order they were discovered in.
+func planDir(zd *ZyraDir) (*runPlan, error) {
+	index := make(map[string]int, len(zd.files))
+	for i, f := range zd.files {
+		index[filepath.Clean(f.File)] = i
+	}
+
+	edges := make([][]int, len(zd.files))
+	for i, f := range zd.files {
+		for _, dep := range f.Doc.Depends {
+			path := filepath.Clean(filepath.Join(filepath.Dir(f.File), dep))
+			j, ok := index[path]
+			if !ok {
+				return nil, fmt.Errorf("file %s: unknown dependency %s", f.File, dep)
+			}
+			if j == i {
+				return nil, fmt.Errorf("file %s: depends on itself", f.File)
+			}
+			edges[i] = append(edges[i], j)
+		}
+	}
+
+	if cycle := findCycle(zd.files, edges); cycle != nil {
+		return nil, fmt.Errorf("dependency cycle: %s", strings.Join(cycle, " -> "))
+	}
+
+	order := topoSort(edges)
+
+	position := make([]int, len(order))
+	for pos, i := range order {
+		position[i] = pos
+	}
+
+	plan := &runPlan{
+		files: make([]ZyraFile, len(order)),
+		deps:  make([][]int, len(order)),
+	}
+	for pos, i := range order {
+		plan.files[pos] = zd.files[i]
+		for _, j := range edges[i] {
+			plan.deps[pos] = append(plan.deps[pos], position[j])
+		}
+	}
+
+	return plan, nil
+}
+
+// topoSort orders the nodes so every node comes after its dependencies,
+// always picking the lowest ready index to keep the order stable.
+func topoSort(edges [][]int) []int {
+	n := len(edges)
+	pending := make([]int, n)
+	dependents := make([][]int, n)
+	for i, deps := range edges {
+		pending[i] = len(deps)
+		for _, j := range deps {
+			dependents[j] = append(dependents[j], i)
+		}
+	}
+
+	done := make([]bool, n)
+	order := make([]int, 0, n)
+	for len(order) < n {
+		next := -1
+		for i := 0; i < n; i++ {
+			if !done[i] && pending[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			break
+		}
+
+		done[next] = true
+		order = append(order, next)
+		for _, d := range dependents[next] {
+			pending[d]--
+		}
+	}
+	return order
+}
+
+func findCycle(files []ZyraFile, edges [][]int) []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make([]int, len(edges))
+	var stack []int
+	var cycle []string
+
+	var visit func(i int) bool
+	visit = func(i int) bool {
+		state[i] = visiting
+		stack = append(stack, i)
+
+		for _, j := range edges[i] {
+			switch state[j] {
+			case visiting:
+				start := 0
+				for k, s := range stack {
+					if s == j {
+						start = k
+						break
+					}
+				}
+				for _, s := range stack[start:] {
+					cycle = append(cycle, files[s].File)
+				}
+				cycle = append(cycle, files[j].File)
+				return true
+
+			case unvisited:
+				if visit(j) {
+					return true
+				}
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[i] = visited
+		return false
+	}
+
+	for i := range edges {
+		if state[i] == unvisited && visit(i) {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// groups splits the plan into sets of files connected through
+// dependencies. Each group keeps the plan order, so running a group
+// front to back respects every dependency inside it.
+func (p *runPlan) groups() [][]int {
+	parent := make([]int, len(p.files))
+	for i := range parent {
+		parent[i] = i
+	}
+
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i, deps := range p.deps {
+		for _, j := range deps {
+			parent[find(i)] = find(j)
+		}
+	}
+
+	var groups [][]int
+	groupOf := make(map[int]int)
+	for i := range p.files {
+		root := find(i)
+		g, ok := groupOf[root]
+		if !ok {
+			g = len(groups)
+			groupOf[root] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], i)
+	}
+	return groups
+}
+
+// failedDependency returns the first dependency of file i that failed.
+func (p *runPlan) failedDependency(i int, failed []bool) (string, bool) {
+	for _, j := range p.deps[i] {
+		if failed[j] {
+			return p.files[j].File, true
+		}
+	}
+	return "", false
+}
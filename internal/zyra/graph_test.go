@@ -0,0 +1,151 @@
+package zyra
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/assert/builtin"
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/parser"
+)
+
+// testDir builds a directory from file names and their depends, every
+// file holding a GET of url plus path.
+func testDir(t *testing.T, url string, files [][2]string) *ZyraDir {
+	t.Helper()
+
+	zd := &ZyraDir{}
+	for _, f := range files {
+		src := ""
+		if f[1] != "" {
+			src = "[meta]\ndepends = " + f[1] + "\n\n"
+		}
+		src += "GET " + url + "/" + strings.TrimSuffix(f[0], ".zyra") + "\n\n[assert]\nstatus eq 200\n"
+
+		doc, err := parser.ParseDocument(src)
+		if err != nil {
+			t.Fatalf("%s: %v", f[0], err)
+		}
+		zd.files = append(zd.files, ZyraFile{File: "dir/" + f[0], Doc: doc})
+	}
+	return zd
+}
+
+func planNames(plan *runPlan) []string {
+	names := make([]string, len(plan.files))
+	for i, f := range plan.files {
+		names[i] = strings.TrimPrefix(f.File, "dir/")
+	}
+	return names
+}
+
+func TestPlanDirChain(t *testing.T) {
+	zd := testDir(t, "", [][2]string{
+		{"a.zyra", "c.zyra"},
+		{"b.zyra", ""},
+		{"c.zyra", "d.zyra"},
+		{"d.zyra", ""},
+	})
+
+	plan, err := planDir(zd)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"b.zyra", "d.zyra", "c.zyra", "a.zyra"}
+	if got := planNames(plan); !reflect.DeepEqual(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+	if want := [][]int{nil, nil, {1}, {2}}; !reflect.DeepEqual(plan.deps, want) {
+		t.Errorf("deps = %v, want %v", plan.deps, want)
+	}
+	if want := [][]int{{0}, {1, 2, 3}}; !reflect.DeepEqual(plan.groups(), want) {
+		t.Errorf("groups = %v, want %v", plan.groups(), want)
+	}
+}
+
+func TestPlanDirErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files [][2]string
+		want  string
+	}{
+		{
+			name:  "cycle",
+			files: [][2]string{{"a.zyra", "b.zyra"}, {"b.zyra", "c.zyra"}, {"c.zyra", "a.zyra"}},
+			want:  "dependency cycle: dir/a.zyra -> dir/b.zyra -> dir/c.zyra -> dir/a.zyra",
+		},
+		{
+			name:  "self",
+			files: [][2]string{{"a.zyra", "a.zyra"}},
+			want:  "file dir/a.zyra: depends on itself",
+		},
+		{
+			name:  "missing",
+			files: [][2]string{{"a.zyra", "login.zyra"}},
+			want:  "file dir/a.zyra: unknown dependency login.zyra",
+		},
+	}
+
+	for _, tt := range tests {
+		_, err := planDir(testDir(t, "", tt.files))
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("%s: err = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestRunDirSkipsFailedDependencies(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer srv.Close()
+
+	builtin.InitBuiltin()
+
+	files := [][2]string{
+		{"login.zyra", ""},
+		{"me.zyra", "login.zyra"},
+		{"orders.zyra", "me.zyra"},
+		{"health.zyra", ""},
+	}
+
+	runners := map[string]func(*ZyraDir, *Zyra) ([]ZyraResult, error){
+		"sync":     runDirSync,
+		"parallel": func(zd *ZyraDir, z *Zyra) ([]ZyraResult, error) { return runDirConcurrent(zd, z, 2) },
+	}
+
+	for name, run := range runners {
+		results, err := run(testDir(t, srv.URL, files), NewZyra(nil, false))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		got := make(map[string]string)
+		for _, r := range results {
+			file := strings.TrimPrefix(r.File, "dir/")
+			switch {
+			case r.Skipped:
+				got[file] = "skipped: " + r.SkipReason
+			case len(r.Errors) > 0:
+				got[file] = "failed"
+			default:
+				got[file] = "passed"
+			}
+		}
+
+		want := map[string]string{
+			"login.zyra":  "failed",
+			"me.zyra":     "skipped: dependency dir/login.zyra did not pass",
+			"orders.zyra": "skipped: dependency dir/me.zyra did not pass",
+			"health.zyra": "passed",
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: results = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package zyra
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/model"
+)
+
+// operationName matches the name of the first named operation.
+var operationName = regexp.MustCompile(`(?m)^\s*(?:query|mutation|subscription)\s+([_A-Za-z][_0-9A-Za-z]*)`)
+
+type graphqlRequest struct {
+	Query         string          `json:"query"`
+	Variables     json.RawMessage `json:"variables,omitempty"`
+	OperationName string          `json:"operationName,omitempty"`
+}
+
+// graphqlBody wraps a [graphql] section into the standard POST body.
+func graphqlBody(g *model.GraphQL) (string, error) {
+	body := graphqlRequest{Query: strings.TrimSpace(g.Query)}
+
+	if vars := strings.TrimSpace(g.Variables); vars != "" {
+		if !json.Valid([]byte(vars)) {
+			return "", fmt.Errorf("line %d: [graphql.variables] is not valid JSON", g.VariablesPos.Line)
+		}
+		body.Variables = json.RawMessage(vars)
+	}
+
+	if m := operationName.FindStringSubmatch(body.Query); m != nil {
+		body.OperationName = m[1]
+	}
+
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(body); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
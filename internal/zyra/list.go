@@ -23,24 +23,31 @@ func ListZyraFiles(options ListZyraFilesOptions) error {
 	}
 
 	if options.ListCount {
-		fmt.Printf("Requests: %v\n", len(zDir.files))
+		count := 0
+		for _, f := range zDir.files {
+			count += len(f.Doc.Requests)
+		}
+		fmt.Printf("Requests: %v\n", count)
 		return nil
 	}
 
 	list := make([]logger.RequestMeta, 0, len(zDir.files))
 	for _, f := range zDir.files {
-		if options.ListPattern != "" && !(strings.Contains(f.File, options.ListPattern) || strings.Contains(f.Doc.Path, options.ListPattern)) {
-			continue
+		for i, req := range f.Doc.Requests {
+			if options.ListPattern != "" && !(strings.Contains(f.File, options.ListPattern) || strings.Contains(req.Path, options.ListPattern)) {
+				continue
+			}
+			list = append(list, logger.RequestMeta{
+				FilePath:   f.File,
+				Name:       requestName(f, i),
+				Method:     req.Method,
+				URL:        req.Path,
+				Assertions: len(req.Assertions),
+				HasHeaders: len(req.Headers) > 0,
+				HasVars:    len(req.Vars) > 0,
+				HasBody:    req.HasBody(),
+			})
 		}
-		list = append(list, logger.RequestMeta{
-			FilePath:   f.File,
-			Method:     f.Doc.Method,
-			URL:        f.Doc.Path,
-			Assertions: len(f.Doc.Assertions),
-			HasHeaders: len(f.Doc.Headers) > 0,
-			HasVars:    len(f.Doc.Vars) > 0,
-			HasBody:    len(f.Doc.Body) > 0,
-		})
 	}
 
 	if options.ListJSON {
@@ -1,6 +1,10 @@
 package zyra
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
@@ -13,6 +17,10 @@ import (
 type ZyraFile struct {
 	File string
 	Doc  *model.Document
+
+	// jar holds the cookies of the file's `cookies = isolated` requests,
+	// set by processFile for each run of the file.
+	jar http.CookieJar
 }
 
 type ZyraDir struct {
@@ -22,8 +30,12 @@ type ZyraDir struct {
 
 const zyraExt = ".zyra"
 
-func loadConfig(path string) (*parser.Config, error) {
+// loadConfig reads the config at path and applies the env profile.
+func loadConfig(path string, env string) (*parser.Config, error) {
 	if path == "" {
+		if env != "" {
+			return nil, fmt.Errorf("--env %s requires a %s", env, configFileName)
+		}
 		return nil, nil
 	}
 
@@ -32,7 +44,47 @@ func loadConfig(path string) (*parser.Config, error) {
 		return nil, err
 	}
 
-	return parser.ParseConfig(string(data))
+	config, err := parser.ParseConfig(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	config.Dir = filepath.Dir(path)
+
+	if err := config.UseProfile(env); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// loadDotEnv reads dir/.env and, for a profile, dir/.env.<profile> on
+// top of it. Missing files are skipped.
+func loadDotEnv(dir string, profile string) (map[string]string, error) {
+	files := []string{filepath.Join(dir, ".env")}
+	if profile != "" {
+		files = append(files, filepath.Join(dir, ".env."+profile))
+	}
+
+	env := make(map[string]string)
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		values, err := parser.ParseDotEnv(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f, err)
+		}
+		for k, v := range values {
+			env[k] = v
+		}
+	}
+
+	return env, nil
 }
 
 func loadDoc(path string) (*model.Document, error) {
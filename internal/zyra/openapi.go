@@ -0,0 +1,60 @@
+package zyra
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	httpclient "github.com/Mahmoud-Khaled-FS/zyra/internal/httpClient"
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/model"
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/openapi"
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/parser"
+)
+
+// loadSpec reads the spec of the `openapi` option, relative to the
+// config. It returns nil when the option is not set.
+func loadSpec(config *parser.Config) (*openapi.Spec, error) {
+	if config == nil || config.Options["openapi"] == "" {
+		return nil, nil
+	}
+
+	path := config.Options["openapi"]
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(config.Dir, path)
+	}
+	return openapi.Load(path)
+}
+
+// checkContract validates zr against the operation of the request and
+// reports it as one assertion on the request line.
+func (z *Zyra) checkContract(result *ZyraResult, req *model.Request, method, rawURL string, zr *httpclient.ZyraResponse) {
+	path := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		path = u.Path
+	}
+
+	op, errs := z.Spec.Validate(method, path, zr.Status, zr.Headers, zr.Body)
+
+	source := "openapi " + strings.ToUpper(method) + " " + path
+	if op != nil {
+		source = "openapi " + op.String()
+	}
+
+	var err error
+	if len(errs) > 0 {
+		msgs := make([]string, len(errs))
+		for i, e := range errs {
+			msgs[i] = e.Error()
+		}
+
+		// the source is not printed with failures, name the operation
+		prefix := "openapi"
+		if op != nil {
+			prefix = source
+		}
+		err = fmt.Errorf("%s: %s", prefix, strings.Join(msgs, "; "))
+	}
+
+	result.addAssertion(&model.Assertion{Line: req.Line, Source: source}, false, err)
+}
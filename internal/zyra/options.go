@@ -0,0 +1,66 @@
+package zyra
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"path/filepath"
+
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/model"
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/parser"
+)
+
+// pathOptions are [options] keys holding file paths.
+var pathOptions = []string{"ca_cert", "client_cert", "client_key"}
+
+// requestOptions merges the config [options] with the request ones,
+// the request value winning. Relative paths are resolved against the
+// file that declared them.
+func requestOptions(config *parser.Config, req *model.Request, file string) map[string]string {
+	options := make(map[string]string, len(config.Options)+len(req.Options))
+
+	for k, v := range config.Options {
+		options[k] = v
+	}
+	resolveOptionPaths(options, config.Dir)
+
+	docOptions := make(map[string]string, len(req.Options))
+	for k, v := range req.Options {
+		docOptions[k] = v
+	}
+	resolveOptionPaths(docOptions, filepath.Dir(file))
+
+	for k, v := range docOptions {
+		options[k] = v
+	}
+	return options
+}
+
+func resolveOptionPaths(options map[string]string, dir string) {
+	for _, key := range pathOptions {
+		v, ok := options[key]
+		if !ok || v == "" || filepath.IsAbs(v) {
+			continue
+		}
+		options[key] = filepath.Join(dir, v)
+	}
+}
+
+// cookieJar picks the jar for the `cookies` option of a request of zf:
+// shared (default) uses the run jar, isolated the jar of the file and
+// off disables cookies.
+func (z *Zyra) cookieJar(mode string, zf ZyraFile) (http.CookieJar, error) {
+	switch mode {
+	case "", "shared":
+		return z.Jar, nil
+	case "isolated":
+		if zf.jar != nil {
+			return zf.jar, nil
+		}
+		return cookiejar.New(nil)
+	case "off":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("invalid option cookies: %q", mode)
+	}
+}
@@ -0,0 +1,183 @@
+package zyra
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	httpclient "github.com/Mahmoud-Khaled-FS/zyra/internal/httpClient"
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/utils"
+)
+
+// RunReport is everything a reporter needs to describe a run.
+type RunReport struct {
+	Results  []ZyraResult
+	Duration time.Duration
+	Verbose  bool
+}
+
+// Reporter writes a run report in a specific format.
+type Reporter interface {
+	Report(w io.Writer, report *RunReport) error
+}
+
+const (
+	ReporterPretty = "pretty"
+	ReporterJUnit  = "junit"
+	ReporterJSON   = "json"
+	ReporterTAP    = "tap"
+)
+
+func NewReporter(name string) (Reporter, error) {
+	switch name {
+	case "", ReporterPretty:
+		return prettyReporter{}, nil
+	case ReporterJUnit:
+		return junitReporter{}, nil
+	case ReporterJSON:
+		return jsonReporter{}, nil
+	case ReporterTAP:
+		return tapReporter{}, nil
+	default:
+		return nil, fmt.Errorf("unknown reporter: %s", name)
+	}
+}
+
+// writeReport writes the report with the selected reporter. When the
+// report goes to a file, the pretty output is still printed to stdout.
+func writeReport(options RunOption, report *RunReport) error {
+	reporter, err := NewReporter(options.Reporter)
+	if err != nil {
+		return err
+	}
+
+	if options.Output == "" {
+		return reporter.Report(os.Stdout, report)
+	}
+
+	f, err := os.Create(options.Output)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := reporter.Report(f, report); err != nil {
+		return err
+	}
+
+	if _, ok := reporter.(prettyReporter); !ok {
+		return prettyReporter{}.Report(os.Stdout, report)
+	}
+	return nil
+}
+
+type summary struct {
+	Total   int
+	Passed  int
+	Failed  int
+	Skipped int
+}
+
+func summarize(results []ZyraResult) summary {
+	s := summary{Total: len(results)}
+	for _, r := range results {
+		switch {
+		case r.Skipped:
+			s.Skipped++
+		case len(r.Errors) > 0:
+			s.Failed++
+		default:
+			s.Passed++
+		}
+	}
+	return s
+}
+
+// assertionName labels an assertion for reports, e.g. line 3: status eq 200.
+func assertionName(a AssertionResult) string {
+	name := fmt.Sprintf("line %d: %s", a.Line, a.Source)
+	if a.Global {
+		return "[global] " + name
+	}
+	return name
+}
+
+// assertionMessage is the failure reason of an assertion without the
+// line prefix added by assert.Evaluate.
+func assertionMessage(a AssertionResult) string {
+	if inner := errors.Unwrap(a.Err); inner != nil {
+		return inner.Error()
+	}
+	return a.Err.Error()
+}
+
+const (
+	green  = "\033[32m"
+	red    = "\033[31m"
+	yellow = "\033[33m"
+	reset  = "\033[0m"
+	bold   = "\033[1m"
+)
+
+type prettyReporter struct{}
+
+func (prettyReporter) Report(w io.Writer, report *RunReport) error {
+	BeautyLogger(w, report)
+	return nil
+}
+
+func printTiming(w io.Writer, t httpclient.Timing) {
+	phases := []struct {
+		name string
+		d    time.Duration
+	}{
+		{"DNS", t.DNS},
+		{"Connect", t.Connect},
+		{"TLS", t.TLS},
+		{"TTFB", t.TTFB},
+		{"Download", t.Download},
+	}
+	for _, p := range phases {
+		fmt.Fprintf(w, "    %-9s %s\n", p.name+":", utils.PrettyDuration(p.d))
+	}
+}
+
+func BeautyLogger(w io.Writer, report *RunReport) {
+	for _, res := range report.Results {
+		fmt.Fprintf(w, "%sFile:%s %s\n", bold, reset, res.Name())
+
+		if res.Skipped {
+			fmt.Fprintf(w, "  %s➜ SKIPPED%s %s\n", yellow, reset, res.SkipReason)
+		} else if len(res.Errors) == 0 {
+			fmt.Fprintf(w, "  %s✔ PASSED%s\n", green, reset)
+		} else {
+			fmt.Fprintf(w, "  %s✖ FAILED%s\n", red, reset)
+			for i, err := range res.Errors {
+				fmt.Fprintf(w, "    %d) %s\n", i+1, err.Error())
+			}
+		}
+
+		if res.Response != nil {
+			fmt.Fprintf(w, "  Response Status: %d\n", res.Response.Status)
+			fmt.Fprintf(w, "  Response Duration: %s\n", utils.PrettyDuration(res.Response.Duration))
+			if report.Verbose {
+				printTiming(w, res.Response.Timing)
+			}
+		}
+
+		fmt.Fprintln(w, strings.Repeat("-", 40))
+	}
+
+	s := summarize(report.Results)
+	fmt.Fprintf(
+		w,
+		"%sPassed: %d%s  %sFailed: %d%s  %sSkipped: %d%s  Duration: %s\n",
+		green, s.Passed, reset,
+		red, s.Failed, reset,
+		yellow, s.Skipped, reset,
+		utils.PrettyDuration(report.Duration),
+	)
+}
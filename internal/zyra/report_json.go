@@ -0,0 +1,135 @@
+package zyra
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+type jsonReporter struct{}
+
+type jsonReport struct {
+	Summary    jsonSummary  `json:"summary"`
+	DurationMs float64      `json:"durationMs"`
+	Results    []jsonResult `json:"results"`
+}
+
+type jsonSummary struct {
+	Total   int `json:"total"`
+	Passed  int `json:"passed"`
+	Failed  int `json:"failed"`
+	Skipped int `json:"skipped"`
+}
+
+type jsonResult struct {
+	File       string          `json:"file"`
+	Request    string          `json:"request,omitempty"`
+	Status     string          `json:"status"`
+	DurationMs float64         `json:"durationMs"`
+	SkipReason string          `json:"skipReason,omitempty"`
+	Response   *jsonResponse   `json:"response,omitempty"`
+	Assertions []jsonAssertion `json:"assertions"`
+	Errors     []string        `json:"errors"`
+}
+
+type jsonResponse struct {
+	Status     int        `json:"status"`
+	DurationMs float64    `json:"durationMs"`
+	Timing     jsonTiming `json:"timing"`
+}
+
+type jsonTiming struct {
+	DNSMs      float64 `json:"dnsMs"`
+	ConnectMs  float64 `json:"connectMs"`
+	TLSMs      float64 `json:"tlsMs"`
+	TTFBMs     float64 `json:"ttfbMs"`
+	DownloadMs float64 `json:"downloadMs"`
+	TotalMs    float64 `json:"totalMs"`
+}
+
+type jsonAssertion struct {
+	Line      int    `json:"line"`
+	Assertion string `json:"assertion"`
+	Global    bool   `json:"global"`
+	Passed    bool   `json:"passed"`
+	Error     string `json:"error,omitempty"`
+}
+
+func (jsonReporter) Report(w io.Writer, report *RunReport) error {
+	s := summarize(report.Results)
+	out := jsonReport{
+		Summary: jsonSummary{
+			Total:   s.Total,
+			Passed:  s.Passed,
+			Failed:  s.Failed,
+			Skipped: s.Skipped,
+		},
+		DurationMs: milliseconds(report.Duration),
+		Results:    make([]jsonResult, 0, len(report.Results)),
+	}
+
+	for _, r := range report.Results {
+		res := jsonResult{
+			File:       r.File,
+			Request:    r.requestLabel(),
+			Status:     resultStatus(r),
+			DurationMs: milliseconds(r.Duration),
+			SkipReason: r.SkipReason,
+			Assertions: make([]jsonAssertion, 0, len(r.Assertions)),
+			Errors:     make([]string, 0, len(r.Errors)),
+		}
+
+		if r.Response != nil {
+			res.Response = &jsonResponse{
+				Status:     r.Response.Status,
+				DurationMs: milliseconds(r.Response.Duration),
+				Timing: jsonTiming{
+					DNSMs:      milliseconds(r.Response.Timing.DNS),
+					ConnectMs:  milliseconds(r.Response.Timing.Connect),
+					TLSMs:      milliseconds(r.Response.Timing.TLS),
+					TTFBMs:     milliseconds(r.Response.Timing.TTFB),
+					DownloadMs: milliseconds(r.Response.Timing.Download),
+					TotalMs:    milliseconds(r.Response.Timing.Total),
+				},
+			}
+		}
+
+		for _, a := range r.Assertions {
+			ja := jsonAssertion{
+				Line:      a.Line,
+				Assertion: a.Source,
+				Global:    a.Global,
+				Passed:    a.Passed(),
+			}
+			if a.Err != nil {
+				ja.Error = assertionMessage(a)
+			}
+			res.Assertions = append(res.Assertions, ja)
+		}
+
+		for _, err := range r.Errors {
+			res.Errors = append(res.Errors, err.Error())
+		}
+
+		out.Results = append(out.Results, res)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func resultStatus(r ZyraResult) string {
+	switch {
+	case r.Skipped:
+		return "skipped"
+	case len(r.Errors) > 0:
+		return "failed"
+	default:
+		return "passed"
+	}
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,136 @@
+package zyra
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type junitReporter struct{}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+	Body    string `xml:",chardata"`
+}
+
+// Report writes one test case per file. Failed assertions go into
+// <failure>, other errors into <error> and every assertion outcome
+// into <system-out>.
+func (junitReporter) Report(w io.Writer, report *RunReport) error {
+	suite := junitTestSuite{
+		Name:      "zyra",
+		Time:      junitSeconds(report.Duration),
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	for _, r := range report.Results {
+		tc := junitTestCase{
+			Name:      r.Name(),
+			ClassName: filepath.Dir(r.File),
+			Time:      junitSeconds(r.Duration),
+		}
+		suite.Tests++
+
+		if r.Skipped {
+			tc.Skipped = &junitMessage{Message: r.SkipReason}
+			suite.Skipped++
+			suite.Cases = append(suite.Cases, tc)
+			continue
+		}
+
+		var out, failures []string
+		for _, a := range r.Assertions {
+			if a.Passed() {
+				out = append(out, "PASS "+assertionName(a))
+				continue
+			}
+			out = append(out, "FAIL "+assertionName(a)+": "+assertionMessage(a))
+			failures = append(failures, assertionName(a)+": "+assertionMessage(a))
+		}
+		tc.SystemOut = strings.Join(out, "\n")
+
+		if errs := r.otherErrors(); len(errs) > 0 {
+			lines := make([]string, len(errs))
+			for i, err := range errs {
+				lines[i] = err.Error()
+			}
+			tc.Error = &junitMessage{
+				Message: lines[0],
+				Type:    "Error",
+				Body:    strings.Join(lines, "\n"),
+			}
+			suite.Errors++
+		} else if len(failures) > 0 {
+			tc.Failure = &junitMessage{
+				Message: fmt.Sprintf("%d assertion(s) failed", len(failures)),
+				Type:    "AssertionError",
+				Body:    strings.Join(failures, "\n"),
+			}
+			suite.Failures++
+		}
+
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	doc := junitTestSuites{
+		Name:     suite.Name,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Errors:   suite.Errors,
+		Skipped:  suite.Skipped,
+		Time:     suite.Time,
+		Suites:   []junitTestSuite{suite},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
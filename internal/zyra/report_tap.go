@@ -0,0 +1,70 @@
+package zyra
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+type tapReporter struct{}
+
+// Report writes TAP version 13 with one test point per file and one
+// subtest point per assertion.
+func (tapReporter) Report(w io.Writer, report *RunReport) error {
+	var b strings.Builder
+
+	b.WriteString("TAP version 13\n")
+	fmt.Fprintf(&b, "1..%d\n", len(report.Results))
+
+	for i, r := range report.Results {
+		n := i + 1
+
+		if r.Skipped {
+			fmt.Fprintf(&b, "ok %d - %s # SKIP %s\n", n, r.Name(), r.SkipReason)
+			continue
+		}
+
+		if len(r.Assertions) > 0 {
+			fmt.Fprintf(&b, "# Subtest: %s\n", r.Name())
+			fmt.Fprintf(&b, "    1..%d\n", len(r.Assertions))
+			for j, a := range r.Assertions {
+				if a.Passed() {
+					fmt.Fprintf(&b, "    ok %d - %s\n", j+1, assertionName(a))
+					continue
+				}
+				fmt.Fprintf(&b, "    not ok %d - %s\n", j+1, assertionName(a))
+				writeTapDiagnostic(&b, "      ", []string{assertionMessage(a)})
+			}
+		}
+
+		if len(r.Errors) == 0 {
+			fmt.Fprintf(&b, "ok %d - %s\n", n, r.Name())
+			continue
+		}
+
+		fmt.Fprintf(&b, "not ok %d - %s\n", n, r.Name())
+		if errs := r.otherErrors(); len(errs) > 0 {
+			messages := make([]string, len(errs))
+			for i, err := range errs {
+				messages[i] = err.Error()
+			}
+			writeTapDiagnostic(&b, "  ", messages)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeTapDiagnostic(b *strings.Builder, indent string, messages []string) {
+	fmt.Fprintf(b, "%s---\n", indent)
+	if len(messages) == 1 {
+		fmt.Fprintf(b, "%smessage: %q\n", indent, messages[0])
+	} else {
+		fmt.Fprintf(b, "%smessages:\n", indent)
+		for _, m := range messages {
+			fmt.Fprintf(b, "%s  - %q\n", indent, m)
+		}
+	}
+	fmt.Fprintf(b, "%s...\n", indent)
+}
@@ -2,44 +2,130 @@ package zyra
 
 import (
 	"fmt"
-	"strings"
+	"time"
 
 	httpclient "github.com/Mahmoud-Khaled-FS/zyra/internal/httpClient"
-	"github.com/Mahmoud-Khaled-FS/zyra/internal/utils"
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/model"
 )
 
 type ZyraResult struct {
-	Errors   []error
-	File     string
+	Errors []error
+	File   string
+
+	// Request is the name given after ###, Index the request position
+	// in the file and Requests how many the file holds.
+	Request  string
+	Index    int
+	Requests int
+
 	Response *httpclient.ZyraResponse
+	Duration time.Duration
+
+	// Assertions holds the outcome of every evaluated assertion,
+	// global ones first.
+	Assertions []AssertionResult
+
+	Skipped    bool
+	SkipReason string
 }
 
-const (
-	green  = "\033[32m"
-	red    = "\033[31m"
-	yellow = "\033[33m"
-	reset  = "\033[0m"
-	bold   = "\033[1m"
-)
+type AssertionResult struct {
+	Line   int
+	Source string
+	Global bool
+	Err    error
+}
 
-func BeautyLogger(results []ZyraResult) {
-	for _, res := range results {
-		fmt.Printf("%sFile:%s %s\n", bold, reset, res.File)
+func (r AssertionResult) Passed() bool {
+	return r.Err == nil
+}
 
-		if len(res.Errors) == 0 {
-			fmt.Printf("  %s✔ PASSED%s\n", green, reset)
-		} else {
-			fmt.Printf("  %s✖ FAILED%s\n", red, reset)
-			for i, err := range res.Errors {
-				fmt.Printf("    %d) %s\n", i+1, err.Error())
-			}
+func (r *ZyraResult) addAssertion(a *model.Assertion, global bool, err error) {
+	r.Assertions = append(r.Assertions, AssertionResult{
+		Line:   a.Line,
+		Source: a.Source,
+		Global: global,
+		Err:    err,
+	})
+
+	if err != nil {
+		r.Errors = append(r.Errors, err)
+	}
+}
+
+// requestName names the i-th request of f the way reports do.
+func requestName(f ZyraFile, i int) string {
+	r := ZyraResult{Request: f.Doc.Requests[i].Name, Index: i, Requests: len(f.Doc.Requests)}
+	return r.requestLabel()
+}
+
+func newResult(f ZyraFile, req *model.Request) ZyraResult {
+	r := ZyraResult{
+		File:     f.File,
+		Request:  req.Name,
+		Requests: len(f.Doc.Requests),
+	}
+	for i, dr := range f.Doc.Requests {
+		if dr == req {
+			r.Index = i
 		}
+	}
+	return r
+}
+
+// Name identifies the result in reports: the file alone, or the file
+// and the request name (#n when unnamed) for files with several requests.
+func (r ZyraResult) Name() string {
+	label := r.requestLabel()
+	if label == "" {
+		return r.File
+	}
+	return r.File + " > " + label
+}
 
-		if res.Response != nil {
-			fmt.Printf("  Response Status: %d\n", res.Response.Status)
-			fmt.Printf("  Response Duration: %s\n", utils.PrettyDuration(res.Response.Duration))
+// requestLabel names the request inside a file holding several, empty
+// otherwise.
+func (r ZyraResult) requestLabel() string {
+	if r.Requests <= 1 {
+		return ""
+	}
+	if r.Request != "" {
+		return r.Request
+	}
+	return fmt.Sprintf("#%d", r.Index+1)
+}
+
+func (r ZyraResult) Passed() bool {
+	return !r.Skipped && len(r.Errors) == 0
+}
+
+// otherErrors returns the errors that do not come from an assertion,
+// like transport, resolve or capture failures.
+func (r ZyraResult) otherErrors() []error {
+	var errs []error
+	for _, err := range r.Errors {
+		fromAssertion := false
+		for _, a := range r.Assertions {
+			if a.Err == err {
+				fromAssertion = true
+				break
+			}
 		}
+		if !fromAssertion {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
 
-		fmt.Println(strings.Repeat("-", 40))
+// skippedResults returns one skipped result per request of f.
+func skippedResults(f ZyraFile, dependency string) []ZyraResult {
+	results := make([]ZyraResult, 0, len(f.Doc.Requests))
+	for _, req := range f.Doc.Requests {
+		r := newResult(f, req)
+		r.Skipped = true
+		r.SkipReason = fmt.Sprintf("dependency %s did not pass", dependency)
+		results = append(results, r)
 	}
+	return results
 }
@@ -1,12 +1,16 @@
 package zyra
 
 import (
-	"fmt"
+	"net/http/cookiejar"
 	"os"
+	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/Mahmoud-Khaled-FS/zyra/internal/assert/builtin"
 	"github.com/Mahmoud-Khaled-FS/zyra/internal/parser"
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/resolver"
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/snapshot"
 )
 
 const configFileName = "zyra.config"
@@ -15,12 +19,29 @@ type RunOption struct {
 	Path       string
 	ConfigPath string
 	NoTest     bool
+	Parallel   int
+	Reporter   string
+	Output     string
+	Env        string
+
+	// Vars are --var key=value overrides.
+	Vars map[string]string
+
+	// Verbose adds the timing breakdown to the pretty output.
+	Verbose bool
+
+	// UpdateSnapshots rewrites the snapshots that no longer match.
+	UpdateSnapshots bool
 }
 
 func Run(options RunOption) error {
 	stat, err := os.Stat(options.Path)
 	if err != nil {
-		return err
+		return configFailure(err)
+	}
+
+	if _, err := NewReporter(options.Reporter); err != nil {
+		return configFailure(err)
 	}
 
 	builtin.InitBuiltin()
@@ -33,120 +54,268 @@ func Run(options RunOption) error {
 }
 
 func RunFile(options RunOption) error {
-	var config *parser.Config = nil
+	start := time.Now()
 
-	if options.ConfigPath != "" {
-		var err error
-		config, err = loadConfig(options.ConfigPath)
-		if err != nil {
-			return err
-		}
+	config, err := loadConfig(options.ConfigPath, options.Env)
+	if err != nil {
+		return configFailure(err)
 	}
 
 	doc, err := loadDoc(options.Path)
 	if err != nil {
-		return err
+		return configFailure(err)
 	}
 
-	z := NewZyra(config, options.NoTest)
-	r, err := z.Process(ZyraFile{
+	z, err := newRunZyra(options, config, filepath.Dir(options.Path))
+	if err != nil {
+		return configFailure(err)
+	}
+	defer z.Transports.CloseIdleConnections()
+
+	results := z.processFile(ZyraFile{
 		File: options.Path,
 		Doc:  doc,
 	})
+
+	if err := z.Snapshots.Prune(); err != nil {
+		return configFailure(err)
+	}
+
+	err = writeReport(options, &RunReport{
+		Results:  results,
+		Duration: time.Since(start),
+		Verbose:  options.Verbose,
+	})
 	if err != nil {
 		return err
 	}
 
-	results := make([]ZyraResult, 1)
-	results[0] = r
-
-	BeautyLogger(results)
-
-	return err
+	return runFailure(results)
 }
 
 func RunDir(options RunOption) error {
+	start := time.Now()
+
 	zDir, err := loadDir(options.Path)
 	if err != nil {
-		return err
+		return configFailure(err)
 	}
 
 	if options.ConfigPath != "" {
 		zDir.configPath = options.ConfigPath
 	}
 
-	var config *parser.Config = nil
+	config, err := loadConfig(zDir.configPath, options.Env)
+	if err != nil {
+		return configFailure(err)
+	}
 
-	if zDir.configPath != "" {
-		config, err = loadConfig(zDir.configPath)
-		if err != nil {
-			return err
-		}
+	z, err := newRunZyra(options, config, options.Path)
+	if err != nil {
+		return configFailure(err)
 	}
+	defer z.Transports.CloseIdleConnections()
 
-	results, err := runDirSync(zDir, config, options.NoTest)
+	var results []ZyraResult
+	if options.Parallel > 1 {
+		results, err = runDirConcurrent(zDir, z, options.Parallel)
+	} else {
+		results, err = runDirSync(zDir, z)
+	}
+	if err != nil {
+		return configFailure(err)
+	}
+
+	if err := z.Snapshots.Prune(); err != nil {
+		return configFailure(err)
+	}
+
+	err = writeReport(options, &RunReport{
+		Results:  results,
+		Duration: time.Since(start),
+		Verbose:  options.Verbose,
+	})
 	if err != nil {
 		return err
 	}
 
-	BeautyLogger(results)
-	return nil
+	return runFailure(results)
+}
+
+// newRunZyra creates the Zyra of a run with the .env values found next
+// to the config, or in dir when there is no config.
+func newRunZyra(options RunOption, config *parser.Config, dir string) (*Zyra, error) {
+	if config != nil {
+		dir = config.Dir
+	}
+
+	env, err := loadDotEnv(dir, options.Env)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := loadSpec(config)
+	if err != nil {
+		return nil, err
+	}
+
+	z := NewZyra(config, options.NoTest)
+	z.Env = env
+	z.Vars = options.Vars
+	z.Spec = spec
+	z.Snapshots.Update = options.UpdateSnapshots
+	return z, nil
 }
 
-func runDirSync(zd *ZyraDir, config *parser.Config, noTest bool) ([]ZyraResult, error) {
+func runDirSync(zd *ZyraDir, z *Zyra) ([]ZyraResult, error) {
+	plan, err := planDir(zd)
+	if err != nil {
+		return nil, err
+	}
 
-	z := NewZyra(config, noTest)
-	results := make([]ZyraResult, len(zd.files))
+	results := make([][]ZyraResult, len(plan.files))
+	failed := make([]bool, len(plan.files))
+
+	// captures are kept per group as in runDirConcurrent, so a run gives
+	// the same results with and without --parallel
+	seed := z.Captures.Values()
+	groups := plan.groups()
+	groupZyra := make([]*Zyra, len(plan.files))
+	for _, group := range groups {
+		gz := z.withCaptures(seed)
+		for _, i := range group {
+			groupZyra[i] = gz
+		}
+	}
+
+	for i, f := range plan.files {
+		if dep, ok := plan.failedDependency(i, failed); ok {
+			results[i] = skippedResults(f, dep)
+			failed[i] = true
+			continue
+		}
 
-	for i, f := range zd.files {
-		r, err := z.Process(f)
+		results[i] = groupZyra[i].processFile(f)
+		failed[i] = anyFailed(results[i])
+	}
+	return flatten(results), nil
+}
+
+// processFile runs the requests of a file in order and reports a
+// processing error as a failed result, so one broken request never
+// stops the rest of the run.
+func (z *Zyra) processFile(f ZyraFile) []ZyraResult {
+	results := make([]ZyraResult, 0, len(f.Doc.Requests))
+
+	// cookiejar.New only fails on a bad public suffix list.
+	f.jar, _ = cookiejar.New(nil)
+
+	for _, req := range f.Doc.Requests {
+		start := time.Now()
+
+		r, err := z.Process(f, req)
 		if err != nil {
-			return results, fmt.Errorf("file %s: %w", f.File, err)
+			r = newResult(f, req)
+			r.Errors = []error{&configError{err: err}}
+		}
+
+		r.Duration = time.Since(start)
+		results = append(results, r)
+	}
+
+	if !z.NoTest && allResponded(results) {
+		z.Snapshots.Done(snapshot.File(f.File))
+	}
+	return results
+}
+
+// allResponded reports whether every request got a response, so all
+// of their assertions were evaluated.
+func allResponded(results []ZyraResult) bool {
+	for _, r := range results {
+		if r.Response == nil {
+			return false
 		}
-		results[i] = r
 	}
-	return results, nil
+	return true
 }
 
-func RunDirConcurrent(zd *ZyraDir, config *parser.Config, noTest bool) ([]ZyraResult, error) {
-	z := NewZyra(config, noTest)
-	results := make([]ZyraResult, len(zd.files))
+// withCaptures returns a copy of z capturing into a fresh context
+// holding seed.
+func (z *Zyra) withCaptures(seed map[string]string) *Zyra {
+	cp := *z
+	cp.Captures = resolver.NewContext()
+	cp.Captures.Merge(seed)
+	return &cp
+}
+
+func anyFailed(results []ZyraResult) bool {
+	for _, r := range results {
+		if len(r.Errors) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func flatten(results [][]ZyraResult) []ZyraResult {
+	var out []ZyraResult
+	for _, rs := range results {
+		out = append(out, rs...)
+	}
+	return out
+}
 
+// runDirConcurrent runs independent dependency groups on a pool of
+// workers. Files inside a group run one after another in plan order,
+// so results and skips match runDirSync.
+func runDirConcurrent(zd *ZyraDir, z *Zyra, workers int) ([]ZyraResult, error) {
+	plan, err := planDir(zd)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([][]ZyraResult, len(plan.files))
+	failed := make([]bool, len(plan.files))
+
+	groups := plan.groups()
+	if workers > len(groups) {
+		workers = len(groups)
+	}
+
+	// every group captures into its own context, seeded with the values
+	// known before the run, so groups never see each other's captures
+	seed := z.Captures.Values()
+
+	groupCh := make(chan []int)
 	var wg sync.WaitGroup
-	errCh := make(chan error, len(zd.files))
-	resCh := make(chan struct {
-		Index  int
-		Result ZyraResult
-	}, len(zd.files))
 
-	for i, f := range zd.files {
+	for w := 0; w < workers; w++ {
 		wg.Add(1)
-		go func(idx int, file ZyraFile) {
+		go func() {
 			defer wg.Done()
-			r, err := z.Process(file)
-			if err != nil {
-				errCh <- fmt.Errorf("file %s: %w", file.File, err)
-				return
+			for group := range groupCh {
+				gz := z.withCaptures(seed)
+				for _, i := range group {
+					f := plan.files[i]
+					if dep, ok := plan.failedDependency(i, failed); ok {
+						results[i] = skippedResults(f, dep)
+						failed[i] = true
+						continue
+					}
+
+					results[i] = gz.processFile(f)
+					failed[i] = anyFailed(results[i])
+				}
 			}
-			resCh <- struct {
-				Index  int
-				Result ZyraResult
-			}{Index: idx, Result: r}
-		}(i, f)
+		}()
 	}
 
-	go func() {
-		wg.Wait()
-		close(errCh)
-		close(resCh)
-	}()
-
-	if len(errCh) > 0 {
-		return results, <-errCh
+	for _, group := range groups {
+		groupCh <- group
 	}
+	close(groupCh)
+	wg.Wait()
 
-	for r := range resCh {
-		results[r.Index] = r.Result
-	}
-	return results, nil
+	return flatten(results), nil
 }
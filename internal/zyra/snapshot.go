@@ -0,0 +1,132 @@
+package zyra
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/model"
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/parser"
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/snapshot"
+)
+
+// snapshotIgnore parses the comma separated paths of the
+// snapshot_ignore option, e.g. body.createdAt, headers.Date.
+func snapshotIgnore(option string) ([][]model.PathSegment, error) {
+	var paths [][]model.PathSegment
+	for _, raw := range strings.Split(option, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		path, err := parser.ParsePath(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid option snapshot_ignore: %w", err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// snapshotFunc binds matchesSnapshot to assertion a of req. The
+// snapshot is keyed by the request label and the asserted path, or the
+// name given to matchesSnapshot.
+func (z *Zyra) snapshotFunc(zf ZyraFile, req *model.Request, a *model.Assertion, ignore [][]model.PathSegment) func(string, any) error {
+	return func(name string, actual any) error {
+		root, err := formatPath(a.Path)
+		if err != nil {
+			return err
+		}
+
+		label, err := snapshotLabel(zf, req)
+		if err != nil {
+			return err
+		}
+
+		key := root
+		if name != "" {
+			key = name
+		}
+		if label != "" {
+			key = label + ": " + key
+		}
+
+		value, err := snapshot.Normalize(actual)
+		if err != nil {
+			return err
+		}
+		for _, path := range ignore {
+			if rel, ok := relativePath(path, a.Path); ok {
+				value = snapshot.Omit(value, rel)
+			}
+		}
+
+		return z.Snapshots.Match(snapshot.File(zf.File), key, root, value)
+	}
+}
+
+// snapshotLabel names req in the snapshot keys of a file with several
+// requests: its name after ###, or its method and request line, so
+// inserting or reordering requests keeps the keys.
+func snapshotLabel(zf ZyraFile, req *model.Request) (string, error) {
+	if len(zf.Doc.Requests) <= 1 {
+		return "", nil
+	}
+	if req.Name != "" {
+		return req.Name, nil
+	}
+
+	label := strings.ToUpper(req.Method) + " " + req.Path
+	for _, other := range zf.Doc.Requests {
+		if other != req && other.Name == "" && strings.EqualFold(other.Method, req.Method) && other.Path == req.Path {
+			return "", fmt.Errorf("several requests are %s, name them after ### to use matchesSnapshot", label)
+		}
+	}
+	return label, nil
+}
+
+// relativePath returns the part of path below base, e.g. createdAt for
+// body.createdAt under body. A [*] in path matches any key or index.
+func relativePath(path, base []model.PathSegment) ([]model.PathSegment, bool) {
+	if len(path) <= len(base) {
+		return nil, false
+	}
+
+	headers := base[0].Key != nil && strings.EqualFold(*base[0].Key, "headers")
+	for i, seg := range base {
+		p := path[i]
+		switch {
+		case p.Wildcard && !p.Recursive:
+		case seg.Key != nil && p.Key != nil:
+			if *seg.Key != *p.Key && !((i == 0 || headers) && strings.EqualFold(*seg.Key, *p.Key)) {
+				return nil, false
+			}
+		case seg.Index != nil && p.Index != nil:
+			if *seg.Index != *p.Index {
+				return nil, false
+			}
+		default:
+			return nil, false
+		}
+	}
+	return path[len(base):], true
+}
+
+// formatPath writes a single value path back as text, e.g. body.items[0].
+func formatPath(path []model.PathSegment) (string, error) {
+	var b strings.Builder
+	for i, seg := range path {
+		switch {
+		case seg.Multi():
+			return "", fmt.Errorf("matchesSnapshot needs a path selecting a single value")
+		case seg.Key != nil:
+			if i > 0 {
+				b.WriteByte('.')
+			}
+			b.WriteString(*seg.Key)
+		case seg.Index != nil:
+			fmt.Fprintf(&b, "[%d]", *seg.Index)
+		}
+	}
+	return b.String(), nil
+}
@@ -1,82 +1,177 @@
 package zyra
 
 import (
+	"net/http"
+	"net/http/cookiejar"
+	"path/filepath"
+
 	"github.com/Mahmoud-Khaled-FS/zyra/internal/assert"
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/assert/builtin"
 	httpclient "github.com/Mahmoud-Khaled-FS/zyra/internal/httpClient"
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/model"
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/openapi"
 	"github.com/Mahmoud-Khaled-FS/zyra/internal/parser"
 	"github.com/Mahmoud-Khaled-FS/zyra/internal/resolver"
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/snapshot"
 	"github.com/Mahmoud-Khaled-FS/zyra/internal/utils"
 )
 
 type Zyra struct {
 	Config *parser.Config
 	NoTest bool
+
+	// Captures holds values captured from earlier responses in the run.
+	Captures *resolver.Context
+
+	// Jar is the cookie jar shared by every request of the run.
+	Jar http.CookieJar
+
+	// Env holds values loaded from .env files.
+	Env map[string]string
+
+	// Vars holds --var overrides, they win over every other source.
+	Vars map[string]string
+
+	// Transports are shared by the requests of the run.
+	Transports *httpclient.Transports
+
+	// Snapshots holds the snapshots matchesSnapshot compares against.
+	Snapshots *snapshot.Store
+
+	// Spec is the OpenAPI document responses are checked against, nil
+	// when the config has no openapi option.
+	Spec *openapi.Spec
 }
 
 func NewZyra(config *parser.Config, noTest bool) *Zyra {
 	if config == nil {
 		config = &parser.Config{}
 	}
+	// cookiejar.New only fails on a bad public suffix list.
+	jar, _ := cookiejar.New(nil)
+
 	return &Zyra{
-		Config: config,
-		NoTest: noTest,
+		Config:   config,
+		NoTest:   noTest,
+		Captures: resolver.NewContext(),
+		Jar:      jar,
+
+		Transports: httpclient.NewTransports(),
+		Snapshots:  snapshot.NewStore(false),
 	}
 }
 
-func (z *Zyra) Process(zf ZyraFile) (ZyraResult, error) {
+// Process sends req, one of the requests of zf, and evaluates its
+// assertions.
+func (z *Zyra) Process(zf ZyraFile, req *model.Request) (ZyraResult, error) {
 	ctx := resolver.NewContext()
+	ctx.MergeEnv(z.Env)
 	ctx.Merge(z.Config.Context)
-	if len(zf.Doc.Vars) > 0 {
-		ctx.Merge(zf.Doc.Vars)
+	ctx.MergeLiteral(z.Captures.Values())
+	if len(req.Vars) > 0 {
+		ctx.Merge(req.Vars)
 	}
+	ctx.MergeLiteral(z.Vars)
 
-	doc, err := resolver.ResolveDocument(zf.Doc, ctx)
+	resolved, err := resolver.ResolveRequest(zf.Doc, req, ctx)
 	if err != nil {
 		return ZyraResult{}, err
 	}
 
 	// 2. build request
-	url, err := getRequestUrl(doc.Path, z.Config)
+	options := requestOptions(z.Config, resolved, zf.File)
+
+	url, err := getRequestUrl(resolved.Path, options)
 	if err != nil {
 		return ZyraResult{}, err
 	}
-	req := httpclient.NewRequest(doc.Method, url)
-	req.AddHeaders(doc.Headers)
-	req.AddQueries(doc.Query)
-	req.AddBody(doc.Body)
-	zr, err := req.Run()
 
+	httpOptions, err := httpclient.ParseOptions(options)
 	if err != nil {
 		return ZyraResult{}, err
 	}
 
-	result := ZyraResult{
-		File:     zf.File,
-		Response: zr,
+	client, err := z.Transports.Client(httpOptions)
+	if err != nil {
+		return ZyraResult{}, err
+	}
+
+	ignore, err := snapshotIgnore(options["snapshot_ignore"])
+	if err != nil {
+		return ZyraResult{}, err
 	}
 
+	jar, err := z.cookieJar(options["cookies"], zf)
+	if err != nil {
+		return ZyraResult{}, err
+	}
+
+	body, err := openBody(resolved, filepath.Dir(zf.File), ctx)
+	if err != nil {
+		return ZyraResult{}, err
+	}
+	defer body.Close()
+
+	if err := resolveParts(resolved.Multipart, filepath.Dir(zf.File)); err != nil {
+		return ZyraResult{}, err
+	}
+
+	hr := httpclient.NewRequest(resolved.Method, url)
+	hr.SetOptions(httpOptions)
+	hr.SetClient(client)
+	hr.SetCookieJar(jar)
+	hr.AddHeaders(resolved.Headers)
+	hr.AddQueries(resolved.Query)
+	switch {
+	case resolved.GraphQL != nil:
+		gql, err := graphqlBody(resolved.GraphQL)
+		if err != nil {
+			return ZyraResult{}, err
+		}
+		hr.AddBody(gql)
+		hr.SetContentType("application/json")
+	case len(resolved.Form) > 0:
+		hr.AddForm(resolved.Form)
+	case len(resolved.Multipart) > 0:
+		hr.AddMultipart(resolved.Multipart)
+	default:
+		hr.SetBody(body.reader, body.size)
+	}
+	zr, err := hr.Run()
+	if err != nil {
+		result := newResult(zf, req)
+		result.Errors = []error{err}
+		return result, nil
+	}
+
+	result := newResult(zf, req)
+	result.Response = zr
+
+	result.Errors = append(result.Errors, z.capture(zr, resolved)...)
+
 	if z.NoTest {
 		return result, nil
 	}
 
+	global := builtin.Env{Dir: z.Config.Dir}
 	for _, a := range z.Config.Assertions {
-		err = assert.Evaluate(zr, a)
-		if err != nil {
-			result.Errors = append(result.Errors, err)
-		}
+		result.addAssertion(a, true, assert.Evaluate(zr, a, global))
 	}
 
-	for _, a := range doc.Assertions {
-		err = assert.Evaluate(zr, a)
-		if err != nil {
-			result.Errors = append(result.Errors, err)
-		}
+	local := builtin.Env{Dir: filepath.Dir(zf.File)}
+	for _, a := range resolved.Assertions {
+		local.Snapshot = z.snapshotFunc(zf, req, a, ignore)
+		result.addAssertion(a, false, assert.Evaluate(zr, a, local))
+	}
+
+	if z.Spec != nil {
+		z.checkContract(&result, req, resolved.Method, url, zr)
 	}
 	return result, nil
 }
 
-func getRequestUrl(path string, config *parser.Config) (string, error) {
-	base, ok := config.Options["base_url"]
+func getRequestUrl(path string, options map[string]string) (string, error) {
+	base, ok := options["base_url"]
 	if !ok {
 		return path, nil
 	}
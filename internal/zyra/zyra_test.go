@@ -0,0 +1,55 @@
+package zyra
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/assert/builtin"
+	"github.com/Mahmoud-Khaled-FS/zyra/internal/parser"
+)
+
+func TestProcessHeadResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Total-Count", "42")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	doc, err := parser.ParseDocument(`HEAD ` + srv.URL + `/users
+
+[assert]
+status eq 200
+headers.content-type eq "application/json"
+headers.X-Total-Count eq "42"
+body eq ""
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	builtin.InitBuiltin()
+
+	zf := ZyraFile{File: "head.zyra", Doc: doc}
+	r, err := NewZyra(nil, false).Process(zf, doc.Requests[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(r.Response.RawBody) != 0 {
+		t.Errorf("body = %q, want empty", r.Response.RawBody)
+	}
+	if len(r.Assertions) != 4 {
+		t.Fatalf("assertions = %d, want 4", len(r.Assertions))
+	}
+	for _, a := range r.Assertions {
+		if !a.Passed() {
+			t.Errorf("%s: %v", a.Source, a.Err)
+		}
+	}
+}